@@ -0,0 +1,159 @@
+package float128
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/shogo82148/int128"
+)
+
+// ToBigFloat returns the exact value of f as a *big.Float with the precision of 113 bits.
+// If f is NaN, ToBigFloat panics with [big.ErrNaN].
+func (f Float128) ToBigFloat() *big.Float {
+	if f.IsNaN() {
+		panic(big.ErrNaN{})
+	}
+
+	z := new(big.Float).SetPrec(shift128 + 1)
+	if f.IsInf(0) {
+		return z.SetInf(f.h&signMask128H != 0)
+	}
+	if f.isZero() {
+		if f.h&signMask128H != 0 {
+			z.Neg(z)
+		}
+		return z
+	}
+
+	sign, exp, frac := f.split()
+	z.SetInt(uint128ToBigInt(frac))
+	z.SetMantExp(z, int(exp)-shift128)
+	if sign != 0 {
+		z.Neg(z)
+	}
+	return z
+}
+
+// ToBigRat returns the exact value of f as a *big.Rat.
+// If f is not finite, ToBigRat returns nil.
+func (f Float128) ToBigRat() *big.Rat {
+	if f.IsNaN() || f.IsInf(0) {
+		return nil
+	}
+
+	sign, exp, frac := f.split()
+	z := new(big.Rat).SetInt(uint128ToBigInt(frac))
+	exp -= shift128
+	if exp > 0 {
+		z.SetInt(z.Num().Lsh(z.Num(), uint(exp)))
+	} else if exp < 0 {
+		den := new(big.Int).Lsh(big.NewInt(1), uint(-exp))
+		z.SetFrac(z.Num(), den)
+	}
+	if sign != 0 {
+		z.Neg(z)
+	}
+	return z
+}
+
+// FromBigFloat returns x rounded to Float128 in the rounding mode,
+// and the accuracy of the result.
+// The subnormal numbers and the overflow are handled in the same way as the arithmetic operations,
+// e.g. x beyond the largest finite number is rounded to ±Inf or the largest finite number depending on mode.
+func FromBigFloat(x *big.Float, mode big.RoundingMode) (Float128, big.Accuracy) {
+	var sign uint64
+	if x.Signbit() {
+		sign = signMask128H
+	}
+	if x.IsInf() {
+		return Float128{sign | inf.h, inf.l}, big.Exact
+	}
+	if x.Sign() == 0 {
+		return Float128{sign, 0}, big.Exact
+	}
+
+	// x = mant × 2^exp, 0.5 <= |mant| < 1
+	mant := new(big.Float)
+	exp := x.MantExp(mant)
+
+	// take the most significant 128 bits of mant.
+	mant.SetMantExp(mant, 128)
+	i, acc := mant.Int(nil)
+	var sticky uint64
+	if acc != big.Exact {
+		sticky = 1
+	}
+	i.Abs(i)
+
+	return bigIntToFloat128(sign, i, clampExp(exp-128), sticky, mode)
+}
+
+// FromBigRat returns x rounded to Float128 in the rounding mode,
+// and the accuracy of the result.
+func FromBigRat(x *big.Rat, mode big.RoundingMode) (Float128, big.Accuracy) {
+	var sign uint64
+	if x.Sign() < 0 {
+		sign = signMask128H
+	}
+	if x.IsInt() {
+		return bigIntToFloat128(sign, new(big.Int).Abs(x.Num()), 0, 0, mode)
+	}
+
+	// compute the quotient num / den with at least 128 significant bits.
+	num := new(big.Int).Abs(x.Num())
+	den := new(big.Int).Set(x.Denom())
+	shift := 129 - (num.BitLen() - den.BitLen())
+	if shift >= 0 {
+		num.Lsh(num, uint(shift))
+	} else {
+		den.Lsh(den, uint(-shift))
+	}
+	num.QuoRem(num, den, den)
+	var sticky uint64
+	if den.Sign() != 0 {
+		sticky = 1
+	}
+
+	return bigIntToFloat128(sign, num, clampExp(-shift), sticky, mode)
+}
+
+// FromBigInt returns x rounded to Float128 in the rounding mode,
+// and the accuracy of the result.
+func FromBigInt(x *big.Int, mode big.RoundingMode) (Float128, big.Accuracy) {
+	var sign uint64
+	if x.Sign() < 0 {
+		sign = signMask128H
+	}
+	return bigIntToFloat128(sign, new(big.Int).Abs(x), 0, 0, mode)
+}
+
+// bigIntToFloat128 returns (-1)^sign × x × 2^exp rounded in the rounding mode,
+// and the accuracy of the result.
+// x must not be negative.
+// sticky must be non-zero if the exact value has any non-zero bits below x.
+func bigIntToFloat128(sign uint64, x *big.Int, exp int32, sticky uint64, mode big.RoundingMode) (Float128, big.Accuracy) {
+	if shift := x.BitLen() - 128; shift > 0 {
+		if x.TrailingZeroBits() < uint(shift) {
+			sticky = 1
+		}
+		x = new(big.Int).Rsh(x, uint(shift))
+		exp = clampExp(int(exp) + shift)
+	}
+
+	var buf [16]byte
+	x.FillBytes(buf[:])
+	frac := int128.Uint128{
+		H: binary.BigEndian.Uint64(buf[:8]),
+		L: binary.BigEndian.Uint64(buf[8:]),
+	}
+	f, acc, _ := packRound(sign, exp, frac, sticky, RoundingMode(mode))
+	return f, acc
+}
+
+// uint128ToBigInt returns x as a *big.Int.
+func uint128ToBigInt(x int128.Uint128) *big.Int {
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], x.H)
+	binary.BigEndian.PutUint64(buf[8:], x.L)
+	return new(big.Int).SetBytes(buf[:])
+}
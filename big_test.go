@@ -0,0 +1,325 @@
+package float128
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+var roundingModes = []big.RoundingMode{
+	big.ToNearestEven,
+	big.ToNearestAway,
+	big.ToZero,
+	big.AwayFromZero,
+	big.ToNegativeInf,
+	big.ToPositiveInf,
+}
+
+func TestToBigFloat(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  string
+	}{
+		{Float128{0, 0}, "0x0p+00"},
+		{Float128{signMask128H, 0}, "-0x0p+00"},
+		{Float128{0x3fff_0000_0000_0000, 0}, "0x1p+00"},
+		{Float128{0xc000_8000_0000_0000, 0}, "-0x1.8p+01"},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, "0x1.999999999999999999999999999ap-04"},
+		{Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, "0x1.ffffffffffffffffffffffffffffp+16383"},
+		{Float128{0x0001_0000_0000_0000, 0}, "0x1p-16382"},
+		{Float128{0, 1}, "0x1p-16494"},
+		{Inf(1), "+Inf"},
+		{Inf(-1), "-Inf"},
+	}
+	for _, tt := range tests {
+		got := tt.input.ToBigFloat()
+		if got.Prec() != 113 {
+			t.Errorf("%s.ToBigFloat().Prec() = %d, want 113", dump(tt.input), got.Prec())
+		}
+		if got.Text('x', -1) != tt.want {
+			t.Errorf("%s.ToBigFloat() = %s, want %s", dump(tt.input), got.Text('x', -1), tt.want)
+		}
+	}
+}
+
+func TestToBigFloat_NaN(t *testing.T) {
+	defer func() {
+		if _, ok := recover().(big.ErrNaN); !ok {
+			t.Error("want panic with big.ErrNaN")
+		}
+	}()
+	NaN().ToBigFloat()
+}
+
+func TestToBigRat(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  string
+	}{
+		{Float128{0, 0}, "0/1"},
+		{Float128{signMask128H, 0}, "0/1"},
+		{Float128{0x3fff_0000_0000_0000, 0}, "1/1"},
+		{Float128{0xc000_8000_0000_0000, 0}, "-3/1"},
+		{Float128{0xbffe_8000_0000_0000, 0}, "-3/4"},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, "4153837486827862102824397063376077/41538374868278621028243970633760768"},
+		{Float128{0x406f_0000_0000_0000, 1}, "5192296858534827628530496329220097/1"}, // 2^112 + 1
+	}
+	for _, tt := range tests {
+		got := tt.input.ToBigRat()
+		if got.String() != tt.want {
+			t.Errorf("%s.ToBigRat() = %s, want %s", dump(tt.input), got, tt.want)
+		}
+	}
+
+	if got := NaN().ToBigRat(); got != nil {
+		t.Errorf("NaN().ToBigRat() = %s, want nil", got)
+	}
+	if got := Inf(1).ToBigRat(); got != nil {
+		t.Errorf("Inf(1).ToBigRat() = %s, want nil", got)
+	}
+}
+
+func TestFromBigFloat(t *testing.T) {
+	one := Float128{0x3fff_0000_0000_0000, 0}
+	nextUp := Float128{0x3fff_0000_0000_0000, 1}
+	max := Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}
+	minSub := Float128{0, 1}
+
+	// 1 + 2^-113, the tie between 1 and the next number.
+	tie := new(big.Float).SetPrec(200).SetInt64(1)
+	tie.Add(tie, new(big.Float).SetMantExp(big.NewFloat(1), -113))
+
+	// 2^-16495, half of the smallest subnormal number.
+	halfMinSub := new(big.Float).SetMantExp(big.NewFloat(1), -16495)
+
+	// 2^-20000, far below the smallest subnormal number.
+	tiny := new(big.Float).SetMantExp(big.NewFloat(-1), -20000)
+
+	// 2^16384, just beyond the largest finite number.
+	huge := new(big.Float).SetMantExp(big.NewFloat(1), 16384)
+
+	tests := []struct {
+		input *big.Float
+		mode  big.RoundingMode
+		want  Float128
+		acc   big.Accuracy
+	}{
+		{big.NewFloat(0), big.ToNearestEven, Float128{0, 0}, big.Exact},
+		{new(big.Float).Neg(big.NewFloat(0)), big.ToNearestEven, Float128{signMask128H, 0}, big.Exact},
+		{big.NewFloat(1), big.ToZero, one, big.Exact},
+		{big.NewFloat(math.Inf(-1)), big.ToNearestEven, Inf(-1), big.Exact},
+
+		{tie, big.ToNearestEven, one, big.Below},
+		{tie, big.ToNearestAway, nextUp, big.Above},
+		{tie, big.ToZero, one, big.Below},
+		{tie, big.AwayFromZero, nextUp, big.Above},
+		{tie, big.ToNegativeInf, one, big.Below},
+		{tie, big.ToPositiveInf, nextUp, big.Above},
+
+		{halfMinSub, big.ToNearestEven, Float128{0, 0}, big.Below},
+		{halfMinSub, big.ToNearestAway, minSub, big.Above},
+		{halfMinSub, big.ToZero, Float128{0, 0}, big.Below},
+		{halfMinSub, big.ToPositiveInf, minSub, big.Above},
+
+		{tiny, big.ToNearestEven, Float128{signMask128H, 0}, big.Above},
+		{tiny, big.ToZero, Float128{signMask128H, 0}, big.Above},
+		{tiny, big.AwayFromZero, minSub.Neg(), big.Below},
+		{tiny, big.ToNegativeInf, minSub.Neg(), big.Below},
+		{tiny, big.ToPositiveInf, Float128{signMask128H, 0}, big.Above},
+
+		{huge, big.ToNearestEven, Inf(1), big.Above},
+		{huge, big.ToZero, max, big.Below},
+		{huge, big.ToNegativeInf, max, big.Below},
+		{huge, big.ToPositiveInf, Inf(1), big.Above},
+		{new(big.Float).Neg(huge), big.ToPositiveInf, max.Neg(), big.Above},
+		{new(big.Float).Neg(huge), big.AwayFromZero, Inf(-1), big.Below},
+	}
+	for _, tt := range tests {
+		got, acc := FromBigFloat(tt.input, tt.mode)
+		if got != tt.want || acc != tt.acc {
+			t.Errorf("FromBigFloat(%s, %s) = %s, %s, want %s, %s", tt.input.Text('p', 0), tt.mode, dump(got), acc, dump(tt.want), tt.acc)
+		}
+	}
+}
+
+func TestFromBigInt(t *testing.T) {
+	tests := []struct {
+		input string
+		mode  big.RoundingMode
+		want  Float128
+		acc   big.Accuracy
+	}{
+		{"0", big.ToNearestEven, Float128{0, 0}, big.Exact},
+		{"-3", big.ToNearestEven, Float128{0xc000_8000_0000_0000, 0}, big.Exact},
+		{"10384593717069655257060992658440193", big.ToNearestEven, Float128{0x4070_0000_0000_0000, 0}, big.Below}, // 2^113 + 1
+		{"10384593717069655257060992658440193", big.ToPositiveInf, Float128{0x4070_0000_0000_0000, 1}, big.Above},
+		{"-10384593717069655257060992658440193", big.ToPositiveInf, Float128{0xc070_0000_0000_0000, 0}, big.Above},
+	}
+	for _, tt := range tests {
+		x, _ := new(big.Int).SetString(tt.input, 10)
+		got, acc := FromBigInt(x, tt.mode)
+		if got != tt.want || acc != tt.acc {
+			t.Errorf("FromBigInt(%s, %s) = %s, %s, want %s, %s", tt.input, tt.mode, dump(got), acc, dump(tt.want), tt.acc)
+		}
+	}
+
+	// overflow
+	x := new(big.Int).Lsh(big.NewInt(1), 100000)
+	if got, acc := FromBigInt(x, big.ToNearestEven); got != Inf(1) || acc != big.Above {
+		t.Errorf("FromBigInt(2^100000) = %s, %s, want +Inf, Above", dump(got), acc)
+	}
+}
+
+func TestFromBigRat(t *testing.T) {
+	tests := []struct {
+		input string
+		mode  big.RoundingMode
+		want  Float128
+		acc   big.Accuracy
+	}{
+		{"0", big.ToNearestEven, Float128{0, 0}, big.Exact},
+		{"-3/4", big.ToNearestEven, Float128{0xbffe_8000_0000_0000, 0}, big.Exact},
+		{"1/10", big.ToNearestEven, Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, big.Above},
+		{"1/10", big.ToZero, Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_9999}, big.Below},
+		{"-1/3", big.ToNearestEven, Float128{0xbffd_5555_5555_5555, 0x5555_5555_5555_5555}, big.Above},
+		{"-1/3", big.ToNegativeInf, Float128{0xbffd_5555_5555_5555, 0x5555_5555_5555_5556}, big.Below},
+	}
+	for _, tt := range tests {
+		x, _ := new(big.Rat).SetString(tt.input)
+		got, acc := FromBigRat(x, tt.mode)
+		if got != tt.want || acc != tt.acc {
+			t.Errorf("FromBigRat(%s, %s) = %s, %s, want %s, %s", tt.input, tt.mode, dump(got), acc, dump(tt.want), tt.acc)
+		}
+	}
+
+	// underflow
+	x := new(big.Rat).SetFrac(big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 100000))
+	if got, acc := FromBigRat(x, big.AwayFromZero); got != (Float128{0, 1}) || acc != big.Above {
+		t.Errorf("FromBigRat(2^-100000) = %s, %s, want %s, Above", dump(got), acc, dump(Float128{0, 1}))
+	}
+}
+
+func TestBig_RoundTrip(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 10000
+	if testing.Short() {
+		n = 1000
+	}
+	for i := 0; i < n; i++ {
+		a, b := r.Float128Pair()
+		b.h &^= mask128 << (shift128 - 64) // make it subnormal
+		for _, f := range []Float128{a, b} {
+			if f.IsNaN() {
+				continue
+			}
+			got, acc := FromBigFloat(f.ToBigFloat(), big.ToNearestEven)
+			if got != f || acc != big.Exact {
+				t.Errorf("FromBigFloat(%s.ToBigFloat()) = %s, %s", dump(f), dump(got), acc)
+			}
+			if f.IsInf(0) {
+				continue
+			}
+			got, acc = FromBigRat(f.ToBigRat(), big.ToNearestEven)
+			if !equals(got, f) && !(f.isZero() && got.isZero()) || acc != big.Exact {
+				t.Errorf("FromBigRat(%s.ToBigRat()) = %s, %s", dump(f), dump(got), acc)
+			}
+		}
+	}
+}
+
+func TestFromBigFloat_Random(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 10000
+	if testing.Short() {
+		n = 300
+	}
+	for i := 0; i < n; i++ {
+		// random 200-bit mantissa with random trailing zeros.
+		mant := new(big.Int).SetUint64(r.Uint64() | 1<<63)
+		for j := 0; j < 3; j++ {
+			mant.Lsh(mant, 64)
+			mant.Or(mant, new(big.Int).SetUint64(r.Uint64()))
+		}
+		mant.Rsh(mant, uint(r.Uint64()%256))
+		if r.Uint64()&1 != 0 {
+			mant.Neg(mant)
+		}
+		exp := int(r.Uint64()%33200) - 16700
+		x := new(big.Float).SetInt(mant)
+		x.SetMantExp(x, exp-x.MantExp(nil))
+
+		for _, mode := range roundingModes {
+			want := roundBig(x, mode)
+			got, acc := FromBigFloat(x, mode)
+			if got.ToBigFloat().Cmp(want) != 0 || got.ToBigFloat().Signbit() != want.Signbit() {
+				t.Errorf("FromBigFloat(%s, %s) = %s, want %s", x.Text('p', 0), mode, dump(got), want.Text('p', 0))
+			}
+			if wantAcc := big.Accuracy(want.Cmp(x)); acc != wantAcc {
+				t.Errorf("FromBigFloat(%s, %s) returns accuracy %s, want %s", x.Text('p', 0), mode, acc, wantAcc)
+			}
+
+			q, _ := x.Rat(nil)
+			got2, acc2 := FromBigRat(q, mode)
+			if !got2.Eq(got) || acc2 != acc {
+				t.Errorf("FromBigRat(%s, %s) = %s, %s, want %s, %s", q, mode, dump(got2), acc2, dump(got), acc)
+			}
+		}
+	}
+}
+
+// roundBig rounds x to the nearest binary128 number in the mode, using math/big only.
+func roundBig(x *big.Float, mode big.RoundingMode) *big.Float {
+	// 2^(e-1) <= |x| < 2^e
+	e := x.MantExp(nil)
+
+	// the number of bits between the leading bit and the bit of the smallest subnormal number.
+	prec := e + bias128 + shift128 - 1
+	if prec > shift128+1 {
+		prec = shift128 + 1
+	}
+
+	var z *big.Float
+	if prec > 0 {
+		z = new(big.Float).SetPrec(uint(prec)).SetMode(mode).Set(x)
+	} else {
+		// |x| is less than the smallest subnormal number,
+		// the result is zero or the smallest subnormal number.
+		minSub := new(big.Float).SetMantExp(big.NewFloat(1), -(bias128 + shift128 - 1))
+		half := new(big.Float).SetMantExp(big.NewFloat(1), -(bias128 + shift128))
+		abs := new(big.Float).Abs(x)
+		var up bool
+		switch mode {
+		case big.ToNearestEven:
+			up = abs.Cmp(half) > 0
+		case big.ToNearestAway:
+			up = abs.Cmp(half) >= 0
+		case big.AwayFromZero:
+			up = true
+		case big.ToNegativeInf:
+			up = x.Signbit()
+		case big.ToPositiveInf:
+			up = !x.Signbit()
+		}
+		if up {
+			z = minSub
+		} else {
+			z = new(big.Float)
+		}
+		if x.Signbit() {
+			z.Neg(z)
+		}
+	}
+
+	// overflow
+	if z.MantExp(nil) > bias128+1 {
+		if roundsToInf(0, RoundingMode(mode)) && !x.Signbit() || roundsToInf(signMask128H, RoundingMode(mode)) && x.Signbit() {
+			return new(big.Float).SetInf(x.Signbit())
+		}
+		max := Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}
+		if x.Signbit() {
+			max = max.Neg()
+		}
+		return max.ToBigFloat()
+	}
+	return z
+}
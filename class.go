@@ -0,0 +1,112 @@
+package float128
+
+import "strconv"
+
+// Class is the class of a floating point number, defined by the class operation of IEEE 754.
+type Class byte
+
+// These constants define the classes of IEEE 754, in the same order as IEEE 754.
+const (
+	ClassSignalingNaN      Class = iota // signalingNaN
+	ClassQuietNaN                       // quietNaN
+	ClassNegativeInfinity               // negativeInfinity
+	ClassNegativeNormal                 // negativeNormal
+	ClassNegativeSubnormal              // negativeSubnormal
+	ClassNegativeZero                   // negativeZero
+	ClassPositiveZero                   // positiveZero
+	ClassPositiveSubnormal              // positiveSubnormal
+	ClassPositiveNormal                 // positiveNormal
+	ClassPositiveInfinity               // positiveInfinity
+)
+
+var classNames = [...]string{
+	ClassSignalingNaN:      "signalingNaN",
+	ClassQuietNaN:          "quietNaN",
+	ClassNegativeInfinity:  "negativeInfinity",
+	ClassNegativeNormal:    "negativeNormal",
+	ClassNegativeSubnormal: "negativeSubnormal",
+	ClassNegativeZero:      "negativeZero",
+	ClassPositiveZero:      "positiveZero",
+	ClassPositiveSubnormal: "positiveSubnormal",
+	ClassPositiveNormal:    "positiveNormal",
+	ClassPositiveInfinity:  "positiveInfinity",
+}
+
+// String returns the name of the class in IEEE 754, e.g. "negativeNormal".
+func (c Class) String() string {
+	if int(c) < len(classNames) {
+		return classNames[c]
+	}
+	return "Class(" + strconv.Itoa(int(c)) + ")"
+}
+
+// Class returns the class of f.
+func (f Float128) Class() Class {
+	exp := (f.h >> (shift128 - 64)) & mask128
+	frac := f.h&fracMask128H | f.l
+	neg := f.h&signMask128H != 0
+
+	var c Class
+	switch {
+	case exp == mask128 && frac != 0:
+		if f.h&qNaNBitH == 0 {
+			return ClassSignalingNaN
+		}
+		return ClassQuietNaN
+	case exp == mask128:
+		c = ClassPositiveInfinity
+	case exp != 0:
+		c = ClassPositiveNormal
+	case frac != 0:
+		c = ClassPositiveSubnormal
+	default:
+		c = ClassPositiveZero
+	}
+
+	if neg {
+		// the negative classes are symmetric to the positive ones.
+		c = ClassNegativeInfinity + ClassPositiveInfinity - c
+	}
+	return c
+}
+
+// IsFinite reports whether f is neither infinite nor NaN.
+func (f Float128) IsFinite() bool {
+	return (f.h>>(shift128-64))&mask128 != mask128
+}
+
+// IsNormal reports whether f is a normal number, i.e. finite, non-zero and not subnormal.
+func (f Float128) IsNormal() bool {
+	exp := (f.h >> (shift128 - 64)) & mask128
+	return exp != 0 && exp != mask128
+}
+
+// IsSubnormal reports whether f is a subnormal number.
+func (f Float128) IsSubnormal() bool {
+	exp := (f.h >> (shift128 - 64)) & mask128
+	return exp == 0 && !f.isZero()
+}
+
+// IsZero reports whether f is ±0.
+func (f Float128) IsZero() bool {
+	return f.isZero()
+}
+
+// IsCanonical reports whether f is canonical.
+// All encodings of the binary interchange formats are canonical, so IsCanonical always returns true.
+// It exists for the completeness of the isCanonical operation of IEEE 754.
+func (f Float128) IsCanonical() bool {
+	return true
+}
+
+// Signbit reports whether f is negative or negative zero.
+// Unlike the comparison with zero, it also reports the sign bit of NaN.
+func (f Float128) Signbit() bool {
+	return f.h&signMask128H != 0
+}
+
+// Copysign returns a value with the magnitude of f and the sign of sign.
+// Like [math.Copysign], it only changes the sign bit, so it works for NaN.
+func Copysign(f, sign Float128) Float128 {
+	return Float128{f.h&^signMask128H | sign.h&signMask128H, f.l}
+}
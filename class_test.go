@@ -0,0 +1,135 @@
+package float128
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClass(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  Class
+	}{
+		{Float128{0x7fff_0000_0000_0000, 1}, ClassSignalingNaN},
+		{Float128{0xffff_4000_0000_0000, 0}, ClassSignalingNaN},
+		{NaN(), ClassQuietNaN},
+		{Float128{0xffff_8000_0000_0000, 1}, ClassQuietNaN},
+		{Inf(-1), ClassNegativeInfinity},
+		{Float128{0xbfff_0000_0000_0000, 0}, ClassNegativeNormal},                        // -1
+		{Float128{0xfffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, ClassNegativeNormal},    // -MaxFloat128
+		{Float128{0x8001_0000_0000_0000, 0}, ClassNegativeNormal},                        // -SmallestNormal
+		{Float128{0x8000_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, ClassNegativeSubnormal}, // -LargestSubnormal
+		{Float128{0x8000_0000_0000_0000, 1}, ClassNegativeSubnormal},
+		{Float128{0x8000_0000_0000_0000, 0}, ClassNegativeZero},
+		{Float128{}, ClassPositiveZero},
+		{Float128{0, 1}, ClassPositiveSubnormal},
+		{Float128{0x0000_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, ClassPositiveSubnormal},
+		{Float128{0x0001_0000_0000_0000, 0}, ClassPositiveNormal},
+		{Float128{0x3fff_0000_0000_0000, 0}, ClassPositiveNormal}, // 1
+		{Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, ClassPositiveNormal},
+		{Inf(1), ClassPositiveInfinity},
+	}
+
+	for _, tt := range tests {
+		got := tt.input.Class()
+		if got != tt.want {
+			t.Errorf("%s.Class() = %s, want %s", dump(tt.input), got, tt.want)
+		}
+
+		c := tt.input.Class()
+		if got, want := tt.input.IsNaN(), c == ClassSignalingNaN || c == ClassQuietNaN; got != want {
+			t.Errorf("%s.IsNaN() = %v, want %v", dump(tt.input), got, want)
+		}
+		if got, want := tt.input.IsSignalingNaN(), c == ClassSignalingNaN; got != want {
+			t.Errorf("%s.IsSignalingNaN() = %v, want %v", dump(tt.input), got, want)
+		}
+		if got, want := tt.input.IsInf(0), c == ClassNegativeInfinity || c == ClassPositiveInfinity; got != want {
+			t.Errorf("%s.IsInf(0) = %v, want %v", dump(tt.input), got, want)
+		}
+		if got, want := tt.input.IsFinite(), c >= ClassNegativeNormal && c <= ClassPositiveNormal; got != want {
+			t.Errorf("%s.IsFinite() = %v, want %v", dump(tt.input), got, want)
+		}
+		if got, want := tt.input.IsNormal(), c == ClassNegativeNormal || c == ClassPositiveNormal; got != want {
+			t.Errorf("%s.IsNormal() = %v, want %v", dump(tt.input), got, want)
+		}
+		if got, want := tt.input.IsSubnormal(), c == ClassNegativeSubnormal || c == ClassPositiveSubnormal; got != want {
+			t.Errorf("%s.IsSubnormal() = %v, want %v", dump(tt.input), got, want)
+		}
+		if got, want := tt.input.IsZero(), c == ClassNegativeZero || c == ClassPositiveZero; got != want {
+			t.Errorf("%s.IsZero() = %v, want %v", dump(tt.input), got, want)
+		}
+		if !tt.input.IsCanonical() {
+			t.Errorf("%s.IsCanonical() = false, want true", dump(tt.input))
+		}
+	}
+}
+
+func TestClass_String(t *testing.T) {
+	tests := []struct {
+		input Class
+		want  string
+	}{
+		{ClassSignalingNaN, "signalingNaN"},
+		{ClassQuietNaN, "quietNaN"},
+		{ClassNegativeInfinity, "negativeInfinity"},
+		{ClassNegativeNormal, "negativeNormal"},
+		{ClassNegativeSubnormal, "negativeSubnormal"},
+		{ClassNegativeZero, "negativeZero"},
+		{ClassPositiveZero, "positiveZero"},
+		{ClassPositiveSubnormal, "positiveSubnormal"},
+		{ClassPositiveNormal, "positiveNormal"},
+		{ClassPositiveInfinity, "positiveInfinity"},
+		{Class(42), "Class(42)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.input.String(); got != tt.want {
+			t.Errorf("Class(%d).String() = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestClass_Float64(t *testing.T) {
+	// the predicates agree with the math package.
+	inputs := []float64{
+		0, math.Copysign(0, -1), 1, -1,
+		math.SmallestNonzeroFloat64, -math.SmallestNonzeroFloat64,
+		math.MaxFloat64, -math.MaxFloat64,
+		math.Inf(1), math.Inf(-1), math.NaN(),
+		math.Float64frombits(0xfff8_0000_0000_0001), // -NaN
+	}
+
+	for _, input := range inputs {
+		f := FromFloat64(input)
+		if got, want := f.Signbit(), math.Signbit(input); got != want {
+			t.Errorf("%s.Signbit() = %v, want %v", dump(f), got, want)
+		}
+		if got, want := f.IsFinite(), !math.IsInf(input, 0) && !math.IsNaN(input); got != want {
+			t.Errorf("%s.IsFinite() = %v, want %v", dump(f), got, want)
+		}
+	}
+}
+
+func TestCopysign(t *testing.T) {
+	tests := []struct {
+		f, sign Float128
+		want    Float128
+	}{
+		{Float128{0x3fff_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 0}, Float128{0xbfff_0000_0000_0000, 0}},
+		{Float128{0xbfff_0000_0000_0000, 0}, Float128{}, Float128{0x3fff_0000_0000_0000, 0}},
+		{Float128{0xbfff_0000_0000_0000, 0}, Inf(-1), Float128{0xbfff_0000_0000_0000, 0}},
+		{Float128{}, Float128{0xffff_8000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 0}},
+		{Inf(1), Float128{0xbfff_0000_0000_0000, 0}, Inf(-1)},
+
+		// NaN keeps its payload
+		{Float128{0x7fff_0000_0000_0000, 42}, Float128{0x8000_0000_0000_0000, 0}, Float128{0xffff_0000_0000_0000, 42}},
+		{Float128{0xffff_8000_0000_0000, 42}, NaN(), Float128{0x7fff_8000_0000_0000, 42}},
+	}
+
+	for _, tt := range tests {
+		got := Copysign(tt.f, tt.sign)
+		if got != tt.want {
+			t.Errorf("Copysign(%s, %s) = %s, want %s", dump(tt.f), dump(tt.sign), dump(got), dump(tt.want))
+		}
+	}
+}
@@ -0,0 +1,711 @@
+package float128
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/shogo82148/int128"
+)
+
+// RoundingMode determines how a Float128 value is rounded to the nearest representable value.
+// The values of the rounding modes which [math/big.RoundingMode] also has are the same,
+// so they can be converted to each other.
+type RoundingMode byte
+
+// These constants define supported rounding modes.
+const (
+	ToNearestEven RoundingMode = iota // == IEEE 754-2008 roundTiesToEven
+	ToNearestAway                     // == IEEE 754-2008 roundTiesToAway
+	ToZero                            // == IEEE 754-2008 roundTowardZero
+	AwayFromZero                      // no IEEE 754-2008 equivalent
+	ToNegativeInf                     // == IEEE 754-2008 roundTowardNegative
+	ToPositiveInf                     // == IEEE 754-2008 roundTowardPositive
+	ToOdd                             // no IEEE 754-2008 equivalent; the inexact results are rounded to odd
+)
+
+var roundingModeNames = [...]string{
+	ToNearestEven: "ToNearestEven",
+	ToNearestAway: "ToNearestAway",
+	ToZero:        "ToZero",
+	AwayFromZero:  "AwayFromZero",
+	ToNegativeInf: "ToNegativeInf",
+	ToPositiveInf: "ToPositiveInf",
+	ToOdd:         "ToOdd",
+}
+
+func (mode RoundingMode) String() string {
+	if int(mode) < len(roundingModeNames) {
+		return roundingModeNames[mode]
+	}
+	return "RoundingMode(" + strconv.Itoa(int(mode)) + ")"
+}
+
+// Flags is a set of the exception flags of IEEE 754.
+// The values are the same as the exception flags of Berkeley SoftFloat.
+type Flags uint8
+
+// These constants define the exception flags.
+const (
+	// FlagInexact is raised if the rounded result differs from the exact result.
+	FlagInexact Flags = 1 << iota
+
+	// FlagUnderflow is raised if the result is tiny and inexact.
+	// The tininess is detected after rounding.
+	FlagUnderflow
+
+	// FlagOverflow is raised if the rounded result exceeds the largest finite number.
+	FlagOverflow
+
+	// FlagDivByZero is raised if an exact infinite result is produced from finite operands,
+	// e.g. 1 / 0.
+	FlagDivByZero
+
+	// FlagInvalid is raised if the operation has no usefully definable result,
+	// e.g. 0 / 0, or if an operand is a signaling NaN.
+	FlagInvalid
+)
+
+var flagNames = [...]string{
+	"inexact",
+	"underflow",
+	"overflow",
+	"divbyzero",
+	"invalid",
+}
+
+func (flags Flags) String() string {
+	if flags == 0 {
+		return "0"
+	}
+	var names []string
+	for i, name := range flagNames {
+		if flags&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	if rest := flags &^ (1<<len(flagNames) - 1); rest != 0 {
+		names = append(names, "0x"+strconv.FormatUint(uint64(rest), 16))
+	}
+	return strings.Join(names, "|")
+}
+
+// Context is an environment of the arithmetic operations,
+// which has the rounding mode and the exception flags.
+// The zero value is ready to use, and it rounds ties to even with no flags raised.
+//
+// The methods of Float128, e.g. [Float128.Add], always round ties to even,
+// and they don't report any exceptions.
+// Use Context if you need other rounding modes or the exceptions.
+// The variants of the operations which return the flags of the single operation,
+// e.g. [Float128.AddFlags], are also available.
+type Context struct {
+	// Mode is the rounding mode of the operations.
+	Mode RoundingMode
+
+	// Flags is the set of the exception flags raised by the operations.
+	// The flags are sticky, they are never cleared by the operations.
+	Flags Flags
+}
+
+// Add returns the sum a+b rounded in ctx.Mode.
+func (ctx *Context) Add(a, b Float128) Float128 {
+	f, flags := a.AddFlags(b, ctx.Mode)
+	ctx.Flags |= flags
+	return f
+}
+
+// Sub returns the difference a-b rounded in ctx.Mode.
+func (ctx *Context) Sub(a, b Float128) Float128 {
+	f, flags := a.SubFlags(b, ctx.Mode)
+	ctx.Flags |= flags
+	return f
+}
+
+// Mul returns the product a×b rounded in ctx.Mode.
+func (ctx *Context) Mul(a, b Float128) Float128 {
+	f, flags := a.MulFlags(b, ctx.Mode)
+	ctx.Flags |= flags
+	return f
+}
+
+// Quo returns the quotient a/b rounded in ctx.Mode.
+func (ctx *Context) Quo(a, b Float128) Float128 {
+	f, flags := a.QuoFlags(b, ctx.Mode)
+	ctx.Flags |= flags
+	return f
+}
+
+// FMA returns x×y+z, computed with only one rounding in ctx.Mode.
+func (ctx *Context) FMA(x, y, z Float128) Float128 {
+	f, flags := FMAFlags(x, y, z, ctx.Mode)
+	ctx.Flags |= flags
+	return f
+}
+
+// Sqrt returns the square root of x rounded in ctx.Mode.
+func (ctx *Context) Sqrt(x Float128) Float128 {
+	f, flags := x.SqrtFlags(ctx.Mode)
+	ctx.Flags |= flags
+	return f
+}
+
+// Remainder returns the IEEE 754 floating-point remainder of a/b.
+// The result is always exact, so it doesn't depend on ctx.Mode.
+func (ctx *Context) Remainder(a, b Float128) Float128 {
+	f, flags := a.RemainderFlags(b)
+	ctx.Flags |= flags
+	return f
+}
+
+// RoundToInt returns x rounded to an integer in ctx.Mode.
+// It raises [FlagInexact] if the result differs from x.
+func (ctx *Context) RoundToInt(x Float128) Float128 {
+	f, flags := x.RoundToIntFlags(ctx.Mode)
+	ctx.Flags |= flags
+	return f
+}
+
+// ScaleB returns x × 2**n rounded in ctx.Mode.
+func (ctx *Context) ScaleB(x Float128, n int) Float128 {
+	f, flags := x.ScaleBFlags(n, ctx.Mode)
+	ctx.Flags |= flags
+	return f
+}
+
+// Float64 returns x rounded to float64 in ctx.Mode.
+func (ctx *Context) Float64(x Float128) float64 {
+	f, flags := x.Float64Flags(ctx.Mode)
+	ctx.Flags |= flags
+	return f
+}
+
+// Float32 returns x rounded to float32 in ctx.Mode.
+func (ctx *Context) Float32(x Float128) float32 {
+	f, flags := x.Float32Flags(ctx.Mode)
+	ctx.Flags |= flags
+	return f
+}
+
+// FromFloat64 returns the Float128 representation of x.
+// The conversion is exact, but FlagInvalid is raised if x is a signaling NaN.
+func (ctx *Context) FromFloat64(x float64) Float128 {
+	f, flags := FromFloat64Flags(x)
+	ctx.Flags |= flags
+	return f
+}
+
+// FromFloat32 returns the Float128 representation of x.
+// The conversion is exact, but FlagInvalid is raised if x is a signaling NaN.
+func (ctx *Context) FromFloat32(x float32) Float128 {
+	f, flags := FromFloat32Flags(x)
+	ctx.Flags |= flags
+	return f
+}
+
+// FromInt128 returns i rounded to Float128 in ctx.Mode.
+func (ctx *Context) FromInt128(i int128.Int128) Float128 {
+	var sign uint64
+	u := i.Uint128()
+	if i.H < 0 {
+		sign, u = signMask128H, i.Neg().Uint128()
+	}
+	f, _, flags := packRound(sign, 0, u, 0, ctx.Mode)
+	ctx.Flags |= flags
+	return f
+}
+
+// FromUint128 returns i rounded to Float128 in ctx.Mode.
+func (ctx *Context) FromUint128(i int128.Uint128) Float128 {
+	f, _, flags := packRound(0, 0, i, 0, ctx.Mode)
+	ctx.Flags |= flags
+	return f
+}
+
+// zeroSum returns the exact zero sum of the numbers with the signs.
+func zeroSum(signA, signB uint64, mode RoundingMode) Float128 {
+	if signA == signB {
+		return Float128{signA, 0}
+	}
+	if mode == ToNegativeInf {
+		return Float128{signMask128H, 0}
+	}
+	return Float128{0, 0}
+}
+
+// AddFlags returns the sum a+b rounded in the rounding mode, and the raised exception flags.
+func (a Float128) AddFlags(b Float128, mode RoundingMode) (Float128, Flags) {
+	if a.IsNaN() || b.IsNaN() {
+		return propagateNaNFlags(a, b)
+	}
+	if a.IsInf(0) {
+		if b.IsInf(0) && (a.h^b.h)&signMask128H != 0 {
+			// ±Inf + ∓Inf = NaN
+			return nan, FlagInvalid
+		}
+		return a, 0
+	}
+	if b.IsInf(0) {
+		return b, 0
+	}
+	if a.isZero() {
+		if b.isZero() {
+			return zeroSum(a.h&signMask128H, b.h&signMask128H, mode), 0
+		}
+		// ±0 + b = b
+		return b, 0
+	}
+	if b.isZero() {
+		// a + ±0 = a
+		return a, 0
+	}
+
+	signA, expA, fracA := a.split()
+	signB, expB, fracB := b.split()
+	return addRound128(signA, expA, fracA, signB, expB, fracB, mode)
+}
+
+// addRound256 returns (-1)^signA × fracA × 2^expA + (-1)^signB × fracB × 2^expB rounded in the rounding mode.
+// fracA and fracB must not be zero, and their bit 255 must be zero.
+func addRound256(signA uint64, expA int32, fracA uint256, signB uint64, expB int32, fracB uint256, mode RoundingMode) (Float128, Flags) {
+	// align the most significant bits to bit 254, and keep bit 255 for the carry.
+	n := fracA.leadingZeros() - 1
+	fracA = fracA.lsh(uint(n))
+	expA -= int32(n)
+	n = fracB.leadingZeros() - 1
+	fracB = fracB.lsh(uint(n))
+	expB -= int32(n)
+
+	if expA < expB {
+		signA, signB = signB, signA
+		expA, expB = expB, expA
+		fracA, fracB = fracB, fracA
+	}
+	fracB = fracB.rshSticky(uint(expA - expB))
+
+	sign := signA
+	var frac uint256
+	if signA == signB {
+		frac = fracA.add(fracB)
+	} else {
+		switch fracA.cmp(fracB) {
+		case 0:
+			return zeroSum(signA, signB, mode), 0
+		case 1:
+			frac = fracA.sub(fracB)
+		case -1:
+			frac = fracB.sub(fracA)
+			sign = signB
+		}
+	}
+	f, _, flags := packRound256(sign, expA, frac, mode)
+	return f, flags
+}
+
+// addRound128 is the same as addRound256, but the fractions are in the format of split,
+// and the sum is done in 128 bits.
+func addRound128(signA uint64, expA int32, fracA int128.Uint128, signB uint64, expB int32, fracB int128.Uint128, mode RoundingMode) (Float128, Flags) {
+	if expA < expB {
+		signA, signB = signB, signA
+		expA, expB = expB, expA
+		fracA, fracB = fracB, fracA
+	}
+
+	// align the most significant bits to bit 126, and keep bit 127 for the carry.
+	// the bits shifted out of fracB are squashed into its least significant bit,
+	// which is far enough below the least significant bit of the result to round it correctly.
+	const margin = 126 - shift128
+	fracA = fracA.Lsh(margin)
+	fracB = fracB.Lsh(margin)
+	if d := uint(expA - expB); d >= 128 {
+		fracB = one
+	} else if d > 0 {
+		lost := fracB.Lsh(128 - d)
+		fracB = fracB.Rsh(d)
+		fracB.L |= squash128(lost)
+	}
+
+	sign := signA
+	var frac int128.Uint128
+	if signA == signB {
+		frac = fracA.Add(fracB)
+	} else {
+		switch fracA.Cmp(fracB) {
+		case 0:
+			return zeroSum(signA, signB, mode), 0
+		case 1:
+			frac = fracA.Sub(fracB)
+		case -1:
+			frac = fracB.Sub(fracA)
+			sign = signB
+		}
+	}
+	f, _, flags := packRound(sign, expA-shift128-margin, frac, 0, mode)
+	return f, flags
+}
+
+// fmaRound returns (-1)^signP × fracP × 2^expP + (-1)^signC × fracC × 2^expC rounded in the rounding mode,
+// where fracP is the exact product of two fractions in the format of split, and fracC is also in the format of split.
+func fmaRound(signP uint64, expP int32, fracP uint256, signC uint64, expC int32, fracC int128.Uint128, mode RoundingMode) (Float128, Flags) {
+	// the most significant bit of fracP is bit 224 or 225, and the one of fracC is bit 112.
+	k := expC - expP
+	switch {
+	case k >= 240:
+		// the product is less than the least significant bit of fracC << margin,
+		// so it only works as a sticky bit.
+		const margin = 126 - shift128
+		frac := fracC.Lsh(margin)
+		if signP == signC {
+			frac.L |= 1
+		} else {
+			frac = frac.Sub(one)
+		}
+		f, _, flags := packRound(signC, expC-margin, frac, 0, mode)
+		return f, flags
+
+	case k > shift128+1:
+		return addRound256(signP, expP, fracP, signC, expC, uint256{c: fracC.H, d: fracC.L}, mode)
+	}
+
+	// align fracC to fracP.
+	// if fracC is shifted to the left, the sum is exact.
+	// otherwise, the bits shifted out of fracC are squashed into its least significant bit.
+	// fracP is shifted by one bit to make it even, so that the squashed bit never cancels a bit of fracP.
+	fracP = fracP.lsh(1)
+	expP--
+	k++
+	var c uint256
+	switch {
+	case k >= 0:
+		c = uint256{c: fracC.H, d: fracC.L}.lsh(uint(k))
+	case k > -128:
+		lost := fracC.Lsh(uint(128 + k))
+		fracC = fracC.Rsh(uint(-k))
+		c = uint256{c: fracC.H, d: fracC.L | squash128(lost)}
+	default:
+		c = uint256{d: 1}
+	}
+
+	sign := signP
+	var frac uint256
+	if signP == signC {
+		frac = fracP.add(c)
+	} else {
+		switch fracP.cmp(c) {
+		case 0:
+			return zeroSum(signP, signC, mode), 0
+		case 1:
+			frac = fracP.sub(c)
+		case -1:
+			frac = c.sub(fracP)
+			sign = signC
+		}
+	}
+	f, _, flags := packRound256(sign, expP, frac, mode)
+	return f, flags
+}
+
+// SubFlags returns the difference a-b rounded in the rounding mode, and the raised exception flags.
+func (a Float128) SubFlags(b Float128, mode RoundingMode) (Float128, Flags) {
+	if b.IsNaN() {
+		// don't change the sign of NaN
+		return propagateNaNFlags(a, b)
+	}
+	return a.AddFlags(b.Neg(), mode)
+}
+
+// MulFlags returns the product a×b rounded in the rounding mode, and the raised exception flags.
+func (a Float128) MulFlags(b Float128, mode RoundingMode) (Float128, Flags) {
+	if a.IsNaN() || b.IsNaN() {
+		return propagateNaNFlags(a, b)
+	}
+
+	sign := (a.h ^ b.h) & signMask128H
+	if a.IsInf(0) || b.IsInf(0) {
+		if a.isZero() || b.isZero() {
+			// ±Inf * ±0 = NaN
+			return nan, FlagInvalid
+		}
+		return Float128{sign | inf.h, inf.l}, 0
+	}
+	if a.isZero() || b.isZero() {
+		return Float128{sign, 0}, 0
+	}
+
+	_, expA, fracA := a.split()
+	_, expB, fracB := b.split()
+	f, _, flags := packRound256(sign, expA+expB-2*shift128, mul128(fracA, fracB), mode)
+	return f, flags
+}
+
+// QuoFlags returns the quotient a/b rounded in the rounding mode, and the raised exception flags.
+func (a Float128) QuoFlags(b Float128, mode RoundingMode) (Float128, Flags) {
+	if a.IsNaN() || b.IsNaN() {
+		return propagateNaNFlags(a, b)
+	}
+
+	sign := (a.h ^ b.h) & signMask128H
+	if a.IsInf(0) {
+		if b.IsInf(0) {
+			// ±Inf / ±Inf = NaN
+			return nan, FlagInvalid
+		}
+		return Float128{sign | inf.h, inf.l}, 0
+	}
+	if b.IsInf(0) {
+		// finite / ±Inf = ±0
+		return Float128{sign, 0}, 0
+	}
+	if b.isZero() {
+		if a.isZero() {
+			// ±0 / ±0 = NaN
+			return nan, FlagInvalid
+		}
+		// finite / ±0 = ±Inf
+		return Float128{sign | inf.h, inf.l}, FlagDivByZero
+	}
+	if a.isZero() {
+		return Float128{sign, 0}, 0
+	}
+
+	_, expA, fracA := a.split()
+	_, expB, fracB := b.split()
+
+	// fracA × 2^127 / fracB is in (2^126, 2^128).
+	q, rem := uint256{a: fracA.H, b: fracA.L}.rsh(1).divMod128(fracB)
+	f, _, flags := packRound(sign, expA-expB-127, int128.Uint128{H: q.c, L: q.d}, squash128(rem), mode)
+	return f, flags
+}
+
+// FMAFlags returns x×y+z, computed with only one rounding in the rounding mode,
+// and the raised exception flags.
+// ±0×±Inf+z raises FlagInvalid even if z is a quiet NaN.
+func FMAFlags(x, y, z Float128, mode RoundingMode) (Float128, Flags) {
+	if x.IsNaN() || y.IsNaN() {
+		f, flags := propagateNaNFlags(x, y)
+		if z.IsNaN() {
+			f, flagsZ := propagateNaNFlags(f, z)
+			return f, flags | flagsZ
+		}
+		return f, flags
+	}
+
+	sign := (x.h ^ y.h) & signMask128H
+	if x.IsInf(0) || y.IsInf(0) {
+		if x.isZero() || y.isZero() {
+			// ±Inf * ±0 is invalid, even if z is a quiet NaN.
+			if z.IsNaN() {
+				f, _ := propagateNaNFlags(z, z)
+				return f, FlagInvalid
+			}
+			return nan, FlagInvalid
+		}
+		if z.IsNaN() {
+			return propagateNaNFlags(z, z)
+		}
+		if z.IsInf(0) && z.h&signMask128H != sign {
+			// ±Inf + ∓Inf = NaN
+			return nan, FlagInvalid
+		}
+		return Float128{sign | inf.h, inf.l}, 0
+	}
+	if z.IsNaN() {
+		return propagateNaNFlags(z, z)
+	}
+	if z.IsInf(0) {
+		return z, 0
+	}
+	if x.isZero() || y.isZero() {
+		if z.isZero() {
+			return zeroSum(sign, z.h&signMask128H, mode), 0
+		}
+		return z, 0
+	}
+
+	_, expX, fracX := x.split()
+	_, expY, fracY := y.split()
+	exp := expX + expY - 2*shift128
+	frac := mul128(fracX, fracY)
+	if z.isZero() {
+		f, _, flags := packRound256(sign, exp, frac, mode)
+		return f, flags
+	}
+
+	signZ, expZ, fracZ := z.split()
+	return fmaRound(sign, exp, frac, signZ, expZ-shift128, fracZ, mode)
+}
+
+// SqrtFlags returns the square root of x rounded in the rounding mode, and the raised exception flags.
+func (x Float128) SqrtFlags(mode RoundingMode) (Float128, Flags) {
+	switch {
+	case x.IsNaN():
+		return propagateNaNFlags(x, x)
+	case x.IsInf(1) || x.isZero():
+		return x, 0
+	case x.h&signMask128H != 0:
+		return nan, FlagInvalid
+	}
+
+	exp, q, rem := x.sqrt()
+	f, _, flags := packRound(0, exp-(shift128+1), q, squash128(rem), mode)
+	return f, flags
+}
+
+// RemainderFlags returns the IEEE 754 floating-point remainder of x/y, and the raised exception flags.
+// See [Float128.Remainder] for the special cases.
+// The result is always exact, so the only exception is the invalid operation.
+func (x Float128) RemainderFlags(y Float128) (Float128, Flags) {
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return propagateNaNFlags(x, y)
+	case x.IsInf(0) || y.isZero():
+		return nan, FlagInvalid
+	case y.IsInf(0):
+		return x, 0
+	}
+	r, _ := remainder(x, y, true)
+	return r, 0
+}
+
+// Float64Flags returns x rounded to float64 in the rounding mode, and the raised exception flags.
+func (x Float128) Float64Flags(mode RoundingMode) (float64, Flags) {
+	if x.IsNaN() {
+		var flags Flags
+		if x.IsSignalingNaN() {
+			flags = FlagInvalid
+		}
+		return x.Float64(), flags
+	}
+	if x.IsInf(0) || x.isZero() {
+		return x.Float64(), 0
+	}
+
+	sign, exp, frac := x.split()
+	b, _, flags := binary64.round(sign, exp-shift128, frac, 0, mode)
+	return math.Float64frombits(sign | b.L), flags
+}
+
+// Float32Flags returns x rounded to float32 in the rounding mode, and the raised exception flags.
+func (x Float128) Float32Flags(mode RoundingMode) (float32, Flags) {
+	if x.IsNaN() {
+		var flags Flags
+		if x.IsSignalingNaN() {
+			flags = FlagInvalid
+		}
+		return x.Float32(), flags
+	}
+	if x.IsInf(0) || x.isZero() {
+		return x.Float32(), 0
+	}
+
+	sign, exp, frac := x.split()
+	b, _, flags := binary32.round(sign, exp-shift128, frac, 0, mode)
+	return math.Float32frombits(uint32(sign>>32) | uint32(b.L)), flags
+}
+
+// FromFloat64Flags returns the Float128 representation of x, and the raised exception flags.
+// The conversion is exact, but FlagInvalid is raised if x is a signaling NaN.
+func FromFloat64Flags(x float64) (Float128, Flags) {
+	var flags Flags
+	b := math.Float64bits(x)
+	if b&^signMask64 > mask64<<shift64 && b&(1<<(shift64-1)) == 0 {
+		flags = FlagInvalid
+	}
+	return FromFloat64(x), flags
+}
+
+// FromFloat32Flags returns the Float128 representation of x, and the raised exception flags.
+// The conversion is exact, but FlagInvalid is raised if x is a signaling NaN.
+func FromFloat32Flags(x float32) (Float128, Flags) {
+	var flags Flags
+	b := math.Float32bits(x)
+	if b&^signMask32 > mask32<<shift32 && b&(1<<(shift32-1)) == 0 {
+		flags = FlagInvalid
+	}
+	return FromFloat32(x), flags
+}
+
+// Int64Flags returns the integer resulting from truncating f towards zero, and the raised exception flags.
+// If f is NaN or the result cannot be represented in an int64,
+// the result is the same as [Float128.Int64], and FlagInvalid is raised.
+// Otherwise, FlagInexact is raised if f is not an integer, as convertToIntegerExactTowardZero of IEEE 754.
+func (f Float128) Int64Flags() (int64, Flags) {
+	i, acc := f.Int64()
+	if f.IsNaN() || !f.Lt(Float128{0x403e_0000_0000_0000, 0}) || !f.Gt(Float128{0xc03e_0000_0000_0000, 0x0002_0000_0000_0000}) {
+		// f is NaN, f >= 2^63, or f <= -2^63-1
+		return i, FlagInvalid
+	}
+	if acc != big.Exact {
+		return i, FlagInexact
+	}
+	return i, 0
+}
+
+// Uint64Flags returns the integer resulting from truncating f towards zero, and the raised exception flags.
+// If f is NaN or the result cannot be represented in a uint64,
+// the result is the same as [Float128.Uint64], and FlagInvalid is raised.
+// Otherwise, FlagInexact is raised if f is not an integer, as convertToIntegerExactTowardZero of IEEE 754.
+func (f Float128) Uint64Flags() (uint64, Flags) {
+	i, acc := f.Uint64()
+	if f.IsNaN() || !f.Lt(Float128{0x403f_0000_0000_0000, 0}) || !f.Gt(Float128{0xbfff_0000_0000_0000, 0}) {
+		// f is NaN, f >= 2^64, or f <= -1
+		return i, FlagInvalid
+	}
+	if acc != big.Exact {
+		return i, FlagInexact
+	}
+	return i, 0
+}
+
+// quietFlags returns the exception flags of the quiet comparison of a and b.
+func quietFlags(a, b Float128) Flags {
+	if a.IsSignalingNaN() || b.IsSignalingNaN() {
+		return FlagInvalid
+	}
+	return 0
+}
+
+// signalingFlags returns the exception flags of the signaling comparison of a and b.
+func signalingFlags(a, b Float128) Flags {
+	if a.IsNaN() || b.IsNaN() {
+		return FlagInvalid
+	}
+	return 0
+}
+
+// EqFlags returns a == b, and the raised exception flags.
+// It is a quiet comparison, FlagInvalid is raised only if a or b is a signaling NaN.
+func (a Float128) EqFlags(b Float128) (bool, Flags) {
+	return a.Eq(b), quietFlags(a, b)
+}
+
+// NeFlags returns a != b, and the raised exception flags.
+// It is a quiet comparison, FlagInvalid is raised only if a or b is a signaling NaN.
+func (a Float128) NeFlags(b Float128) (bool, Flags) {
+	return a.Ne(b), quietFlags(a, b)
+}
+
+// LtFlags returns a < b, and the raised exception flags.
+// It is a signaling comparison, FlagInvalid is raised if a or b is NaN.
+func (a Float128) LtFlags(b Float128) (bool, Flags) {
+	return a.Lt(b), signalingFlags(a, b)
+}
+
+// LeFlags returns a <= b, and the raised exception flags.
+// It is a signaling comparison, FlagInvalid is raised if a or b is NaN.
+func (a Float128) LeFlags(b Float128) (bool, Flags) {
+	return a.Le(b), signalingFlags(a, b)
+}
+
+// GtFlags returns a > b, and the raised exception flags.
+// It is a signaling comparison, FlagInvalid is raised if a or b is NaN.
+func (a Float128) GtFlags(b Float128) (bool, Flags) {
+	return a.Gt(b), signalingFlags(a, b)
+}
+
+// GeFlags returns a >= b, and the raised exception flags.
+// It is a signaling comparison, FlagInvalid is raised if a or b is NaN.
+func (a Float128) GeFlags(b Float128) (bool, Flags) {
+	return a.Ge(b), signalingFlags(a, b)
+}
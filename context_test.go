@@ -0,0 +1,647 @@
+package float128
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+var allRoundingModes = []RoundingMode{
+	ToNearestEven,
+	ToNearestAway,
+	ToZero,
+	AwayFromZero,
+	ToNegativeInf,
+	ToPositiveInf,
+	ToOdd,
+}
+
+func TestRoundingMode_String(t *testing.T) {
+	for _, mode := range roundingModes {
+		if got, want := RoundingMode(mode).String(), mode.String(); got != want {
+			t.Errorf("RoundingMode(%d).String() = %q, want %q", mode, got, want)
+		}
+	}
+	if got, want := ToOdd.String(), "ToOdd"; got != want {
+		t.Errorf("ToOdd.String() = %q, want %q", got, want)
+	}
+	if got, want := RoundingMode(42).String(), "RoundingMode(42)"; got != want {
+		t.Errorf("RoundingMode(42).String() = %q, want %q", got, want)
+	}
+}
+
+func TestFlags_String(t *testing.T) {
+	tests := []struct {
+		flags Flags
+		want  string
+	}{
+		{0, "0"},
+		{FlagInexact, "inexact"},
+		{FlagUnderflow | FlagInexact, "inexact|underflow"},
+		{FlagInvalid | FlagDivByZero | FlagOverflow, "overflow|divbyzero|invalid"},
+		{0xe0 | FlagInvalid, "invalid|0xe0"},
+	}
+	for _, tt := range tests {
+		if got := tt.flags.String(); got != tt.want {
+			t.Errorf("Flags(%#x).String() = %q, want %q", uint8(tt.flags), got, tt.want)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	one := Float128{0x3fff_0000_0000_0000, 0}
+	two := Float128{0x4000_0000_0000_0000, 0}
+	three := Float128{0x4000_8000_0000_0000, 0}
+	max := Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}
+	minNormal := Float128{0x0001_0000_0000_0000, 0}
+	minSub := Float128{0, 1}
+	half := Float128{0x3ffe_0000_0000_0000, 0}
+	quarter := Float128{0x3ffd_0000_0000_0000, 0}
+	epsilon := Float128{0x3f8f_0000_0000_0000, 0} // 2^-112
+	snan := Float128{0x7fff_4000_0000_0000, 0}
+	zero := Float128{0, 0}
+	negZero := Float128{signMask128H, 0}
+
+	tests := []struct {
+		name  string
+		mode  RoundingMode
+		op    func(ctx *Context) Float128
+		want  Float128
+		flags Flags
+	}{
+		// exact operations
+		{"1+2", ToNearestEven, func(ctx *Context) Float128 { return ctx.Add(one, two) }, three, 0},
+		{"3-2", ToZero, func(ctx *Context) Float128 { return ctx.Sub(three, two) }, one, 0},
+		{"1×2", ToOdd, func(ctx *Context) Float128 { return ctx.Mul(one, two) }, two, 0},
+		{"2/2", ToPositiveInf, func(ctx *Context) Float128 { return ctx.Quo(two, two) }, one, 0},
+
+		// the sign of zero sums
+		{"1-1", ToNearestEven, func(ctx *Context) Float128 { return ctx.Sub(one, one) }, zero, 0},
+		{"1-1", ToNegativeInf, func(ctx *Context) Float128 { return ctx.Sub(one, one) }, negZero, 0},
+		{"0+(-0)", ToNearestEven, func(ctx *Context) Float128 { return ctx.Add(zero, negZero) }, zero, 0},
+		{"0+(-0)", ToNegativeInf, func(ctx *Context) Float128 { return ctx.Add(zero, negZero) }, negZero, 0},
+		{"-0+(-0)", ToPositiveInf, func(ctx *Context) Float128 { return ctx.Add(negZero, negZero) }, negZero, 0},
+		{"0×(-1)+0", ToNearestEven, func(ctx *Context) Float128 { return ctx.FMA(zero, one.Neg(), zero) }, zero, 0},
+		{"0×(-1)+0", ToNegativeInf, func(ctx *Context) Float128 { return ctx.FMA(zero, one.Neg(), zero) }, negZero, 0},
+		{"1×1-1", ToNegativeInf, func(ctx *Context) Float128 { return ctx.FMA(one, one, one.Neg()) }, negZero, 0},
+
+		// rounding ties
+		{"1+2^-113", ToNearestEven, func(ctx *Context) Float128 { return ctx.Add(one, epsilon.Mul(half)) }, one, FlagInexact},
+		{"1+2^-113", ToNearestAway, func(ctx *Context) Float128 { return ctx.Add(one, epsilon.Mul(half)) }, Float128{0x3fff_0000_0000_0000, 1}, FlagInexact},
+		{"1+2^-113", ToZero, func(ctx *Context) Float128 { return ctx.Add(one, epsilon.Mul(half)) }, one, FlagInexact},
+		{"1+2^-113", AwayFromZero, func(ctx *Context) Float128 { return ctx.Add(one, epsilon.Mul(half)) }, Float128{0x3fff_0000_0000_0000, 1}, FlagInexact},
+		{"1+2^-113", ToNegativeInf, func(ctx *Context) Float128 { return ctx.Add(one, epsilon.Mul(half)) }, one, FlagInexact},
+		{"1+2^-113", ToPositiveInf, func(ctx *Context) Float128 { return ctx.Add(one, epsilon.Mul(half)) }, Float128{0x3fff_0000_0000_0000, 1}, FlagInexact},
+		{"1+2^-113", ToOdd, func(ctx *Context) Float128 { return ctx.Add(one, epsilon.Mul(half)) }, Float128{0x3fff_0000_0000_0000, 1}, FlagInexact},
+		{"-1-2^-113", ToNegativeInf, func(ctx *Context) Float128 { return ctx.Sub(one.Neg(), epsilon.Mul(half)) }, Float128{0xbfff_0000_0000_0000, 1}, FlagInexact},
+		{"-1-2^-113", ToPositiveInf, func(ctx *Context) Float128 { return ctx.Sub(one.Neg(), epsilon.Mul(half)) }, one.Neg(), FlagInexact},
+
+		// 1/3
+		{"1/3", ToNearestEven, func(ctx *Context) Float128 { return ctx.Quo(one, three) }, Float128{0x3ffd_5555_5555_5555, 0x5555_5555_5555_5555}, FlagInexact},
+		{"1/3", ToPositiveInf, func(ctx *Context) Float128 { return ctx.Quo(one, three) }, Float128{0x3ffd_5555_5555_5555, 0x5555_5555_5555_5556}, FlagInexact},
+		{"1/3", ToOdd, func(ctx *Context) Float128 { return ctx.Quo(one, three) }, Float128{0x3ffd_5555_5555_5555, 0x5555_5555_5555_5555}, FlagInexact},
+		{"2/3", ToOdd, func(ctx *Context) Float128 { return ctx.Quo(two, three) }, Float128{0x3ffe_5555_5555_5555, 0x5555_5555_5555_5555}, FlagInexact},
+
+		// sqrt
+		{"sqrt(2)", ToNearestEven, func(ctx *Context) Float128 { return ctx.Sqrt(two) }, Float128{0x3fff_6a09_e667_f3bc, 0xc908_b2fb_1366_ea95}, FlagInexact},
+		{"sqrt(2)", ToZero, func(ctx *Context) Float128 { return ctx.Sqrt(two) }, Float128{0x3fff_6a09_e667_f3bc, 0xc908_b2fb_1366_ea95}, FlagInexact},
+		{"sqrt(2)", ToPositiveInf, func(ctx *Context) Float128 { return ctx.Sqrt(two) }, Float128{0x3fff_6a09_e667_f3bc, 0xc908_b2fb_1366_ea96}, FlagInexact},
+		{"sqrt(-0)", ToNearestEven, func(ctx *Context) Float128 { return ctx.Sqrt(negZero) }, negZero, 0},
+
+		// overflow
+		{"max×2", ToNearestEven, func(ctx *Context) Float128 { return ctx.Mul(max, two) }, Inf(1), FlagOverflow | FlagInexact},
+		{"max×2", ToZero, func(ctx *Context) Float128 { return ctx.Mul(max, two) }, max, FlagOverflow | FlagInexact},
+		{"max×2", ToOdd, func(ctx *Context) Float128 { return ctx.Mul(max, two) }, max, FlagOverflow | FlagInexact},
+		{"max×-2", ToPositiveInf, func(ctx *Context) Float128 { return ctx.Mul(max, two.Neg()) }, max.Neg(), FlagOverflow | FlagInexact},
+		{"max×-2", ToNegativeInf, func(ctx *Context) Float128 { return ctx.Mul(max, two.Neg()) }, Inf(-1), FlagOverflow | FlagInexact},
+		{"max+ulp/2", ToNearestEven, func(ctx *Context) Float128 { return ctx.Add(max, Float128{0x7f8d_0000_0000_0000, 0}) }, Inf(1), FlagOverflow | FlagInexact},
+		{"max+ulp/2", ToZero, func(ctx *Context) Float128 { return ctx.Add(max, Float128{0x7f8d_0000_0000_0000, 0}) }, max, FlagInexact},
+
+		// underflow
+		{"minNormal/2", ToNearestEven, func(ctx *Context) Float128 { return ctx.Quo(minNormal, two) }, Float128{0x0000_8000_0000_0000, 0}, 0},
+		{"minSub/2", ToNearestEven, func(ctx *Context) Float128 { return ctx.Mul(minSub, half) }, zero, FlagUnderflow | FlagInexact},
+		{"minSub/2", ToPositiveInf, func(ctx *Context) Float128 { return ctx.Mul(minSub, half) }, minSub, FlagUnderflow | FlagInexact},
+		{"minSub/3", ToOdd, func(ctx *Context) Float128 { return ctx.Quo(minSub, three) }, minSub, FlagUnderflow | FlagInexact},
+		{"-minSub/3", ToOdd, func(ctx *Context) Float128 { return ctx.Quo(minSub.Neg(), three) }, minSub.Neg(), FlagUnderflow | FlagInexact},
+
+		// tininess is detected after rounding:
+		// minNormal - 2^-16496 rounds to minNormal with unbounded exponent range.
+		{"minNormal-2^-16496", ToNearestEven, func(ctx *Context) Float128 { return ctx.FMA(minSub, quarter.Neg(), minNormal) }, minNormal, FlagInexact},
+		{"minNormal-2^-16496", ToZero, func(ctx *Context) Float128 { return ctx.FMA(minSub, quarter.Neg(), minNormal) }, Float128{0x0000_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, FlagUnderflow | FlagInexact},
+
+		// division by zero
+		{"1/0", ToNearestEven, func(ctx *Context) Float128 { return ctx.Quo(one, zero) }, Inf(1), FlagDivByZero},
+		{"1/-0", ToNearestEven, func(ctx *Context) Float128 { return ctx.Quo(one, negZero) }, Inf(-1), FlagDivByZero},
+		{"Inf/0", ToNearestEven, func(ctx *Context) Float128 { return ctx.Quo(Inf(1), zero) }, Inf(1), 0},
+
+		// invalid operations
+		{"Inf-Inf", ToNearestEven, func(ctx *Context) Float128 { return ctx.Sub(Inf(1), Inf(1)) }, NaN(), FlagInvalid},
+		{"0×Inf", ToNearestEven, func(ctx *Context) Float128 { return ctx.Mul(zero, Inf(-1)) }, NaN(), FlagInvalid},
+		{"0/0", ToNearestEven, func(ctx *Context) Float128 { return ctx.Quo(zero, negZero) }, NaN(), FlagInvalid},
+		{"Inf/Inf", ToNearestEven, func(ctx *Context) Float128 { return ctx.Quo(Inf(1), Inf(-1)) }, NaN(), FlagInvalid},
+		{"sqrt(-1)", ToNearestEven, func(ctx *Context) Float128 { return ctx.Sqrt(one.Neg()) }, NaN(), FlagInvalid},
+		{"0×Inf+NaN", ToNearestEven, func(ctx *Context) Float128 { return ctx.FMA(zero, Inf(1), NaN()) }, NaN(), FlagInvalid},
+		{"Inf×1-Inf", ToNearestEven, func(ctx *Context) Float128 { return ctx.FMA(Inf(1), one, Inf(-1)) }, NaN(), FlagInvalid},
+
+		// NaN operands
+		{"NaN+1", ToNearestEven, func(ctx *Context) Float128 { return ctx.Add(NaN(), one) }, NaN(), 0},
+		{"sNaN+1", ToNearestEven, func(ctx *Context) Float128 { return ctx.Add(snan, one) }, NaN(), FlagInvalid},
+		{"1×sNaN", ToNearestEven, func(ctx *Context) Float128 { return ctx.Mul(one, snan) }, NaN(), FlagInvalid},
+		{"1×1+sNaN", ToNearestEven, func(ctx *Context) Float128 { return ctx.FMA(one, one, snan) }, NaN(), FlagInvalid},
+		{"sqrt(sNaN)", ToNearestEven, func(ctx *Context) Float128 { return ctx.Sqrt(snan) }, NaN(), FlagInvalid},
+	}
+	for _, tt := range tests {
+		ctx := &Context{Mode: tt.mode}
+		got := tt.op(ctx)
+		if !equals(got, tt.want) || ctx.Flags != tt.flags {
+			t.Errorf("%s in %s: got %s, %s, want %s, %s", tt.name, tt.mode, dump(got), ctx.Flags, dump(tt.want), tt.flags)
+		}
+		if got.IsNaN() && got.IsSignalingNaN() {
+			t.Errorf("%s in %s: got signaling NaN %s", tt.name, tt.mode, dump(got))
+		}
+	}
+}
+
+func TestContext_StickyFlags(t *testing.T) {
+	var ctx Context
+	one := Float128{0x3fff_0000_0000_0000, 0}
+	three := Float128{0x4000_8000_0000_0000, 0}
+
+	ctx.Quo(one, three)
+	ctx.Quo(one, Float128{})
+	ctx.Add(one, one)
+	if want := FlagInexact | FlagDivByZero; ctx.Flags != want {
+		t.Errorf("got %s, want %s", ctx.Flags, want)
+	}
+}
+
+func TestContext_Float64(t *testing.T) {
+	tests := []struct {
+		input Float128
+		mode  RoundingMode
+		want  float64
+		flags Flags
+	}{
+		{Float128{0x3fff_0000_0000_0000, 0}, ToNearestEven, 1, 0},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, ToNearestEven, 0.1, FlagInexact},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, ToZero, math.Nextafter(0.1, 0), FlagInexact},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, ToOdd, math.Nextafter(0.1, 0), FlagInexact},
+		{Float128{0x43fe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, ToNearestEven, math.Inf(1), FlagOverflow | FlagInexact},
+		{Float128{0x43fe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, ToZero, math.MaxFloat64, FlagInexact},
+		{Float128{0x43ff_0000_0000_0000, 0}, ToZero, math.MaxFloat64, FlagOverflow | FlagInexact},
+		{Float128{0x3bcc_0000_0000_0000, 0}, ToNearestEven, 0, FlagUnderflow | FlagInexact},                               // 2^-1075
+		{Float128{0x3bcc_0000_0000_0000, 0}, ToNearestAway, math.SmallestNonzeroFloat64, FlagUnderflow | FlagInexact},     // 2^-1075
+		{Float128{0x3bcd_8000_0000_0000, 0}, ToNearestEven, 2 * math.SmallestNonzeroFloat64, FlagUnderflow | FlagInexact}, // 1.5 × 2^-1074
+		{Float128{0x3bcd_0000_0000_0000, 0}, ToZero, math.SmallestNonzeroFloat64, 0},
+		{Float128{0x7fff_4000_0000_0000, 0}, ToNearestEven, math.NaN(), FlagInvalid},
+		{Inf(-1), ToZero, math.Inf(-1), 0},
+	}
+	for _, tt := range tests {
+		ctx := &Context{Mode: tt.mode}
+		got := ctx.Float64(tt.input)
+		if !(got == tt.want || math.IsNaN(got) && math.IsNaN(tt.want)) || ctx.Flags != tt.flags {
+			t.Errorf("Float64(%s) in %s = %x, %s, want %x, %s", dump(tt.input), tt.mode, got, ctx.Flags, tt.want, tt.flags)
+		}
+	}
+}
+
+func TestContext_Float32(t *testing.T) {
+	tests := []struct {
+		input Float128
+		mode  RoundingMode
+		want  float32
+		flags Flags
+	}{
+		{Float128{0x3fff_0000_0000_0000, 0}, ToNearestEven, 1, 0},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, ToNearestEven, 0.1, FlagInexact},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, ToPositiveInf, 0.1, FlagInexact},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, ToNegativeInf, math.Nextafter32(0.1, 0), FlagInexact},
+		{Float128{0x407f_0000_0000_0000, 0}, ToNearestEven, float32(math.Inf(1)), FlagOverflow | FlagInexact},
+		{Float128{0x407f_0000_0000_0000, 0}, ToZero, math.MaxFloat32, FlagOverflow | FlagInexact},
+		{Float128{0x3f69_0000_0000_0000, 0}, ToPositiveInf, math.SmallestNonzeroFloat32, FlagUnderflow | FlagInexact}, // 2^-150
+	}
+	for _, tt := range tests {
+		ctx := &Context{Mode: tt.mode}
+		got := ctx.Float32(tt.input)
+		if got != tt.want || ctx.Flags != tt.flags {
+			t.Errorf("Float32(%s) in %s = %x, %s, want %x, %s", dump(tt.input), tt.mode, got, ctx.Flags, tt.want, tt.flags)
+		}
+	}
+}
+
+func TestContext_FromFloat(t *testing.T) {
+	var ctx Context
+	if got := ctx.FromFloat64(1); got != (Float128{0x3fff_0000_0000_0000, 0}) || ctx.Flags != 0 {
+		t.Errorf("FromFloat64(1) = %s, %s", dump(got), ctx.Flags)
+	}
+	if got := ctx.FromFloat64(math.NaN()); !got.IsNaN() || ctx.Flags != 0 {
+		t.Errorf("FromFloat64(NaN) = %s, %s", dump(got), ctx.Flags)
+	}
+	if got := ctx.FromFloat64(math.Float64frombits(0x7ff0_0000_0000_0001)); !got.IsNaN() || ctx.Flags != FlagInvalid {
+		t.Errorf("FromFloat64(sNaN) = %s, %s", dump(got), ctx.Flags)
+	}
+
+	ctx = Context{}
+	if got := ctx.FromFloat32(float32(math.Inf(-1))); got != Inf(-1) || ctx.Flags != 0 {
+		t.Errorf("FromFloat32(-Inf) = %s, %s", dump(got), ctx.Flags)
+	}
+	if got := ctx.FromFloat32(math.Float32frombits(0xff80_0001)); !got.IsNaN() || ctx.Flags != FlagInvalid {
+		t.Errorf("FromFloat32(sNaN) = %s, %s", dump(got), ctx.Flags)
+	}
+}
+
+// withExp returns f with the biased exponent e, clamped into the finite range.
+func withExp(f Float128, e int32) Float128 {
+	if e < 0 {
+		e = 0
+	} else if e > mask128-1 {
+		e = mask128 - 1
+	}
+	return Float128{f.h&^(mask128<<(shift128-64)) | uint64(e)<<(shift128-64), f.l}
+}
+
+// biasedExp returns the biased exponent of f.
+func biasedExp(f Float128) int32 {
+	return int32(f.h>>(shift128-64)) & mask128
+}
+
+// roundRat returns x rounded to Float128 in the rounding mode,
+// and the exception flags that the operation should raise.
+func roundRat(x *big.Rat, mode RoundingMode) (Float128, Flags) {
+	m := big.RoundingMode(mode)
+	if mode == ToOdd {
+		m = big.ToZero
+	}
+	f, acc := FromBigRat(x, m)
+	if acc == big.Exact {
+		return f, 0
+	}
+	if mode == ToOdd {
+		f.l |= 1
+	}
+
+	flags := FlagInexact
+	// round with unbounded exponent range
+	r := new(big.Float).SetPrec(shift128 + 1).SetMode(m).SetRat(x)
+	e := r.MantExp(nil) - 1
+	if e > bias128 {
+		flags |= FlagOverflow
+	} else if e < 1-bias128 {
+		flags |= FlagUnderflow
+	}
+	return f, flags
+}
+
+func TestContext_Random(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 3000
+	if testing.Short() {
+		n = 100
+	}
+	check := func(name string, mode RoundingMode, got Float128, flags Flags, exact *big.Rat, args ...Float128) {
+		t.Helper()
+		if exact.Sign() == 0 {
+			return
+		}
+		want, wantFlags := roundRat(exact, mode)
+		if got != want || flags != wantFlags {
+			t.Errorf("%s%s in %s: got %s, %s, want %s, %s", name, dumpArgs(args), mode, dump(got), flags, dump(want), wantFlags)
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		a, b := r.Float128Pair()
+		c, _ := r.Float128Pair()
+
+		// keep the exponents close to make the cancellation and the rounding interesting.
+		ea := int32(r.Uint64()%(mask128)) - 1
+		a = withExp(a, ea)
+		b = withExp(b, ea+int32(r.Uint64()%256)-128)
+		c = withExp(c, ea+biasedExp(b)-bias128+int32(r.Uint64()%256)-128)
+
+		ra, rb, rc := a.ToBigRat(), b.ToBigRat(), c.ToBigRat()
+		sum := new(big.Rat).Add(ra, rb)
+		diff := new(big.Rat).Sub(ra, rb)
+		prod := new(big.Rat).Mul(ra, rb)
+		fma := new(big.Rat).Add(prod, rc)
+		var quo *big.Rat
+		if rb.Sign() != 0 {
+			quo = new(big.Rat).Quo(ra, rb)
+		}
+
+		for _, mode := range allRoundingModes {
+			ctx := &Context{Mode: mode}
+			got := ctx.Add(a, b)
+			check("Add", mode, got, ctx.Flags, sum, a, b)
+
+			ctx = &Context{Mode: mode}
+			got = ctx.Sub(a, b)
+			check("Sub", mode, got, ctx.Flags, diff, a, b)
+
+			ctx = &Context{Mode: mode}
+			got = ctx.Mul(a, b)
+			check("Mul", mode, got, ctx.Flags, prod, a, b)
+
+			ctx = &Context{Mode: mode}
+			got = ctx.FMA(a, b, c)
+			check("FMA", mode, got, ctx.Flags, fma, a, b, c)
+
+			if quo != nil {
+				ctx = &Context{Mode: mode}
+				got = ctx.Quo(a, b)
+				check("Quo", mode, got, ctx.Flags, quo, a, b)
+			}
+		}
+	}
+}
+
+func TestContext_RandomSqrt(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 3000
+	if testing.Short() {
+		n = 100
+	}
+	for i := 0; i < n; i++ {
+		a, _ := r.Float128Pair()
+		a = a.Abs()
+		if a.IsNaN() || a.IsInf(0) || a.isZero() {
+			continue
+		}
+
+		// the square root truncated to 1000 bits.
+		x := a.ToBigFloat()
+		z := new(big.Float).SetPrec(1000).SetMode(big.ToZero).Sqrt(x)
+		if new(big.Float).Mul(z, z).Cmp(x) != 0 {
+			// the exact root is between z and z + ulp,
+			// so add the half of ulp as a sticky bit.
+			z.SetPrec(1001)
+			z.Add(z, new(big.Float).SetMantExp(big.NewFloat(1), z.MantExp(nil)-1001))
+		}
+		exact, _ := z.Rat(nil)
+
+		for _, mode := range allRoundingModes {
+			ctx := &Context{Mode: mode}
+			got := ctx.Sqrt(a)
+			want, wantFlags := roundRat(exact, mode)
+			if got != want || ctx.Flags != wantFlags {
+				t.Errorf("Sqrt(%s) in %s: got %s, %s, want %s, %s", dump(a), mode, dump(got), ctx.Flags, dump(want), wantFlags)
+			}
+		}
+	}
+}
+
+func TestContext_RandomFloat64(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 10000
+	if testing.Short() {
+		n = 1000
+	}
+	for i := 0; i < n; i++ {
+		a, _ := r.Float128Pair()
+		a = withExp(a, bias128-bias64+int32(r.Uint64()%2200)-1100)
+
+		for _, mode := range allRoundingModes {
+			ctx := &Context{Mode: mode}
+			got := ctx.Float64(a)
+
+			m := big.RoundingMode(mode)
+			if mode == ToOdd {
+				m = big.ToZero
+			}
+			want, wantFlags := float64Oracle(a.ToBigFloat(), m, mode == ToOdd)
+			if math.Float64bits(got) != math.Float64bits(want) || ctx.Flags != wantFlags {
+				t.Errorf("Float64(%s) in %s: got %x, %s, want %x, %s", dump(a), mode, got, ctx.Flags, want, wantFlags)
+			}
+		}
+	}
+}
+
+// float64Oracle rounds x to float64 using math/big only.
+func float64Oracle(x *big.Float, mode big.RoundingMode, odd bool) (float64, Flags) {
+	// 2^(e-1) <= |x| < 2^e
+	e := x.MantExp(nil)
+	prec := e + bias64 + shift64 - 1
+	if prec > shift64+1 {
+		prec = shift64 + 1
+	}
+
+	var z *big.Float
+	if prec > 0 {
+		z = new(big.Float).SetPrec(uint(prec)).SetMode(mode).Set(x)
+	} else {
+		// the result is zero or the smallest subnormal number.
+		half := new(big.Float).SetMantExp(big.NewFloat(1), -(bias64 + shift64))
+		abs := new(big.Float).Abs(x)
+		var up bool
+		switch mode {
+		case big.ToNearestEven:
+			up = abs.Cmp(half) > 0
+		case big.ToNearestAway:
+			up = abs.Cmp(half) >= 0
+		case big.AwayFromZero:
+			up = true
+		case big.ToNegativeInf:
+			up = x.Signbit()
+		case big.ToPositiveInf:
+			up = !x.Signbit()
+		}
+		z = new(big.Float)
+		if up {
+			z.SetFloat64(math.SmallestNonzeroFloat64)
+		}
+		if x.Signbit() {
+			z.Neg(z)
+		}
+	}
+	if z.Cmp(x) == 0 && z.MantExp(nil) <= bias64+1 {
+		f, _ := z.Float64()
+		return f, 0
+	}
+
+	flags := FlagInexact
+	unbounded := new(big.Float).SetPrec(shift64 + 1).SetMode(mode).Set(x)
+	if ue := unbounded.MantExp(nil) - 1; ue > bias64 {
+		flags |= FlagOverflow
+		var sign uint64
+		if x.Signbit() {
+			sign = signMask128H
+		}
+		if !roundsToInf(sign, RoundingMode(mode)) || odd {
+			return math.Copysign(math.MaxFloat64, float64(x.Sign())), flags
+		}
+		return math.Inf(x.Sign()), flags
+	} else if ue < 1-bias64 {
+		flags |= FlagUnderflow
+	}
+
+	f, _ := z.Float64()
+	if odd && math.Float64bits(f)&1 == 0 {
+		f = math.Float64frombits(math.Float64bits(f) | 1)
+	}
+	return f, flags
+}
+
+func dumpArgs(args []Float128) string {
+	s := "("
+	for i, arg := range args {
+		if i > 0 {
+			s += ", "
+		}
+		s += dump(arg)
+	}
+	return s + ")"
+}
+
+func TestContext_DefaultMode(t *testing.T) {
+	// the Context in the default mode must agree with the fast methods.
+	r := newXoshiro256pp()
+	n := 100000
+	if testing.Short() {
+		n = 10000
+	}
+	var ctx Context
+	for i := 0; i < n; i++ {
+		a, b := r.Float128Pair()
+		c, _ := r.Float128Pair()
+		b = withExp(b, biasedExp(a)+int32(r.Uint64()%256)-128)
+
+		if got, want := ctx.Add(a, b), a.Add(b); !equals(got, want) {
+			t.Errorf("Add%s: got %s, want %s", dumpArgs([]Float128{a, b}), dump(got), dump(want))
+		}
+		if got, want := ctx.Sub(a, b), a.Sub(b); !equals(got, want) {
+			t.Errorf("Sub%s: got %s, want %s", dumpArgs([]Float128{a, b}), dump(got), dump(want))
+		}
+		if got, want := ctx.Mul(a, c), a.Mul(c); !equals(got, want) {
+			t.Errorf("Mul%s: got %s, want %s", dumpArgs([]Float128{a, c}), dump(got), dump(want))
+		}
+		if got, want := ctx.Quo(a, c), a.Quo(c); !equals(got, want) {
+			t.Errorf("Quo%s: got %s, want %s", dumpArgs([]Float128{a, c}), dump(got), dump(want))
+		}
+		if got, want := ctx.FMA(a, b, c), FMA(a, b, c); !equals(got, want) {
+			t.Errorf("FMA%s: got %s, want %s", dumpArgs([]Float128{a, b, c}), dump(got), dump(want))
+		}
+		if got, want := ctx.Sqrt(a), a.Sqrt(); !equals(got, want) {
+			t.Errorf("Sqrt(%s): got %s, want %s", dump(a), dump(got), dump(want))
+		}
+		if got, want := ctx.Float64(a), a.Float64(); !(got == want || math.IsNaN(got) && math.IsNaN(want)) {
+			t.Errorf("Float64(%s): got %x, want %x", dump(a), got, want)
+		}
+	}
+}
+
+func BenchmarkContext_Add(b *testing.B) {
+	r := newXoshiro256pp()
+	var ctx Context
+	for i := 0; i < b.N; i++ {
+		x, y := r.Float128Pair()
+		ctx.Add(x, y)
+	}
+}
+
+func BenchmarkContext_Mul(b *testing.B) {
+	r := newXoshiro256pp()
+	var ctx Context
+	for i := 0; i < b.N; i++ {
+		x, y := r.Float128Pair()
+		ctx.Mul(x, y)
+	}
+}
+
+func BenchmarkContext_Quo(b *testing.B) {
+	r := newXoshiro256pp()
+	var ctx Context
+	for i := 0; i < b.N; i++ {
+		x, y := r.Float128Pair()
+		ctx.Quo(x, y)
+	}
+}
+
+func TestCompareFlags(t *testing.T) {
+	one := Float128{0x3fff_0000_0000_0000, 0}
+	two := Float128{0x4000_0000_0000_0000, 0}
+	qnan := NaN()
+	snan := Float128{0x7fff_4000_0000_0000, 0}
+
+	tests := []struct {
+		a, b                       Float128
+		eq, ne, lt, le, gt, ge     bool
+		quietFlags, signalingFlags Flags
+	}{
+		{one, two, false, true, true, true, false, false, 0, 0},
+		{one, one, true, false, false, true, false, true, 0, 0},
+		{Float128{signMask128H, 0}, Float128{0, 0}, true, false, false, true, false, true, 0, 0},
+		{qnan, one, false, true, false, false, false, false, 0, FlagInvalid},
+		{one, qnan, false, true, false, false, false, false, 0, FlagInvalid},
+		{snan, one, false, true, false, false, false, false, FlagInvalid, FlagInvalid},
+		{qnan, snan, false, true, false, false, false, false, FlagInvalid, FlagInvalid},
+	}
+	for _, tt := range tests {
+		check := func(name string, got bool, flags Flags, want bool, wantFlags Flags) {
+			t.Helper()
+			if got != want || flags != wantFlags {
+				t.Errorf("%s(%s, %s) = %t, %s, want %t, %s", name, dump(tt.a), dump(tt.b), got, flags, want, wantFlags)
+			}
+		}
+		got, flags := tt.a.EqFlags(tt.b)
+		check("EqFlags", got, flags, tt.eq, tt.quietFlags)
+		got, flags = tt.a.NeFlags(tt.b)
+		check("NeFlags", got, flags, tt.ne, tt.quietFlags)
+		got, flags = tt.a.LtFlags(tt.b)
+		check("LtFlags", got, flags, tt.lt, tt.signalingFlags)
+		got, flags = tt.a.LeFlags(tt.b)
+		check("LeFlags", got, flags, tt.le, tt.signalingFlags)
+		got, flags = tt.a.GtFlags(tt.b)
+		check("GtFlags", got, flags, tt.gt, tt.signalingFlags)
+		got, flags = tt.a.GeFlags(tt.b)
+		check("GeFlags", got, flags, tt.ge, tt.signalingFlags)
+	}
+}
+
+func TestInt64Flags(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  int64
+		flags Flags
+	}{
+		{Float128{0x3fff_8000_0000_0000, 0}, 1, FlagInexact},                                 // 1.5
+		{Float128{0xbffe_0000_0000_0000, 0}, 0, FlagInexact},                                 // -0.5
+		{Float128{0x403d_ffff_ffff_ffff, 0xfffc_0000_0000_0000}, math.MaxInt64, 0},           // 2^63-1
+		{Float128{0x403d_ffff_ffff_ffff, 0xfffe_0000_0000_0000}, math.MaxInt64, FlagInexact}, // 2^63-0.5
+		{Float128{0x403e_0000_0000_0000, 0}, math.MinInt64, FlagInvalid},                     // 2^63
+		{Float128{0xc03e_0000_0000_0000, 0}, math.MinInt64, 0},                               // -2^63
+		{Float128{0xc03e_0000_0000_0000, 0x0001_0000_0000_0000}, math.MinInt64, FlagInexact}, // -2^63-0.5
+		{Float128{0xc03e_0000_0000_0000, 0x0002_0000_0000_0000}, math.MinInt64, FlagInvalid}, // -2^63-1
+		{Inf(1), math.MinInt64, FlagInvalid},
+		{NaN(), math.MinInt64, FlagInvalid},
+	}
+	for _, tt := range tests {
+		got, flags := tt.input.Int64Flags()
+		if got != tt.want || flags != tt.flags {
+			t.Errorf("%s.Int64Flags() = %d, %s, want %d, %s", dump(tt.input), got, flags, tt.want, tt.flags)
+		}
+	}
+}
+
+func TestUint64Flags(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  uint64
+		flags Flags
+	}{
+		{Float128{0x3fff_8000_0000_0000, 0}, 1, FlagInexact},                                  // 1.5
+		{Float128{0xbffe_0000_0000_0000, 0}, 0, FlagInexact},                                  // -0.5
+		{Float128{0x8000_0000_0000_0000, 0}, 0, 0},                                            // -0
+		{Float128{0xbfff_0000_0000_0000, 0}, math.MaxUint64, FlagInvalid},                     // -1
+		{Float128{0x403e_ffff_ffff_ffff, 0xfffe_0000_0000_0000}, math.MaxUint64, 0},           // 2^64-1
+		{Float128{0x403e_ffff_ffff_ffff, 0xffff_0000_0000_0000}, math.MaxUint64, FlagInexact}, // 2^64-0.5
+		{Float128{0x403f_0000_0000_0000, 0}, math.MaxUint64, FlagInvalid},                     // 2^64
+		{Inf(-1), math.MaxUint64, FlagInvalid},
+		{NaN(), math.MaxUint64, FlagInvalid},
+	}
+	for _, tt := range tests {
+		got, flags := tt.input.Uint64Flags()
+		if got != tt.want || flags != tt.flags {
+			t.Errorf("%s.Uint64Flags() = %d, %s, want %d, %s", dump(tt.input), got, flags, tt.want, tt.flags)
+		}
+	}
+}
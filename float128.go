@@ -3,6 +3,7 @@ package float128
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"math/bits"
 
 	"github.com/shogo82148/int128"
@@ -22,6 +23,14 @@ const (
 	qNaNBitH     = (1 << (shift128 - 64 - 1))
 )
 
+const (
+	mask32     = 0xff       // mask for exponent
+	shift32    = 32 - 8 - 1 // shift for exponent
+	bias32     = 127        // bias for exponent
+	signMask32 = 1 << 31    // mask for sign bit
+	fracMask32 = 1<<shift32 - 1
+)
+
 const (
 	mask64     = 0x7ff       // mask for exponent
 	shift64    = 64 - 11 - 1 // shift for exponent
@@ -63,8 +72,9 @@ func FromBits(h, l uint64) Float128 {
 	return Float128{h, l}
 }
 
-// FromFloat128 returns the floating point number corresponding
-// to the IEEE 754 binary representation of f.
+// FromFloat64 returns the Float128 representation of f.
+// The conversion is exact. The sign and the payload of NaN are preserved,
+// and signaling NaNs are converted into quiet NaNs.
 func FromFloat64(f float64) Float128 {
 	b := math.Float64bits(f)
 	sign := b & signMask64
@@ -74,7 +84,10 @@ func FromFloat64(f float64) Float128 {
 	if exp == mask64 {
 		if frac != 0 {
 			// f is NaN
-			return nan
+			return Float128{
+				sign | nan.h | frac>>(shift64-(shift128-64)),
+				frac << (64 - shift64 + (shift128 - 64)),
+			}
 		} else {
 			// f is ±Inf
 			return Float128{
@@ -103,7 +116,10 @@ func FromFloat64(f float64) Float128 {
 	}
 }
 
-// Float64 returns the float64 representation of f.
+// Float64 returns the float64 representation of f,
+// rounding to nearest, ties to even.
+// The sign and the upper bits of the payload of NaN are preserved,
+// and signaling NaNs are converted into quiet NaNs.
 func (f Float128) Float64() float64 {
 	sign := f.h & signMask128H
 	exp := int((f.h >> (shift128 - 64)) & mask128)
@@ -151,6 +167,277 @@ func (f Float128) Float64() float64 {
 	return math.Float64frombits(sign | uint64(exp)<<shift64 | frac.L)
 }
 
+// FromFloat32 returns the Float128 representation of f.
+// The conversion is exact. The sign and the payload of NaN are preserved,
+// and signaling NaNs are converted into quiet NaNs.
+func FromFloat32(f float32) Float128 {
+	b := math.Float32bits(f)
+	sign := uint64(b&signMask32) << 32
+	exp := int((b >> shift32) & mask32)
+	frac := uint64(b & fracMask32)
+
+	if exp == mask32 {
+		if frac != 0 {
+			// f is NaN
+			return Float128{
+				sign | nan.h | frac<<(shift128-64-shift32),
+				0,
+			}
+		} else {
+			// f is ±Inf
+			return Float128{
+				inf.h | sign,
+				inf.l,
+			}
+		}
+	}
+
+	if exp == 0 {
+		// f is subnormal
+		if frac == 0 {
+			return Float128{sign, 0}
+		}
+
+		// normalize f
+		l := bits.Len64(frac)
+		exp = l - shift32
+		frac = (frac << (shift32 + 1 - l)) & fracMask32
+	}
+
+	exp += bias128 - bias32
+	return Float128{
+		sign | uint64(exp)<<(shift128-64) | frac<<(shift128-64-shift32),
+		0,
+	}
+}
+
+// Float32 returns the float32 representation of f,
+// rounding to nearest, ties to even.
+// The sign and the upper bits of the payload of NaN are preserved.
+func (f Float128) Float32() float32 {
+	sign := uint32(f.h>>32) & signMask32
+	exp := int((f.h >> (shift128 - 64)) & mask128)
+	frac := f.h & fracMask128H
+
+	if exp == mask128 {
+		if frac|f.l != 0 {
+			// f is NaN
+			const qNaNBit = 1 << (shift32 - 1)
+			f32 := sign | (mask32 << shift32) | qNaNBit | uint32(frac>>(shift128-64-shift32))
+			return math.Float32frombits(f32)
+		} else {
+			// f is ±Inf
+			return math.Float32frombits(sign | (mask32 << shift32))
+		}
+	}
+
+	_, e, m := f.split()
+	b, _, _ := binary32.round(0, e-shift128, m, 0, ToNearestEven)
+	return math.Float32frombits(sign | uint32(b.L))
+}
+
+// FromInt64 returns the Float128 representation of i.
+// The conversion is exact.
+func FromInt64(i int64) Float128 {
+	if i < 0 {
+		f := FromUint64(-uint64(i))
+		f.h |= signMask128H
+		return f
+	}
+	return FromUint64(uint64(i))
+}
+
+// FromUint64 returns the Float128 representation of i.
+// The conversion is exact.
+func FromUint64(i uint64) Float128 {
+	if i == 0 {
+		return Float128{0, 0}
+	}
+
+	// normalize i so that its most significant bit is bit 112.
+	l := bits.Len64(i)
+	frac := int128.Uint128{L: i}.Lsh(uint(shift128 + 1 - l))
+	exp := uint64(l - 1 + bias128)
+	return Float128{
+		exp<<(shift128-64) | frac.H&fracMask128H,
+		frac.L,
+	}
+}
+
+// Int64 returns the integer resulting from truncating f towards zero.
+// If f cannot be represented in an int64, the result is math.MinInt64, the same as SoftFloat and TestFloat for x86:
+// (math.MinInt64, big.Above) for the negative numbers and (math.MinInt64, big.Below) for the positive numbers.
+// If f is NaN, the result is (math.MinInt64, big.Above).
+// The accuracy is big.Exact if the result is exactly f.
+func (f Float128) Int64() (int64, big.Accuracy) {
+	if f.IsNaN() {
+		return math.MinInt64, big.Above
+	}
+
+	sign, exp, frac := f.split()
+	if exp >= 63 {
+		if sign != 0 {
+			if exp == 63 && frac == (int128.Uint128{H: 1 << (shift128 - 64)}) {
+				// f is exactly -2^63
+				return math.MinInt64, big.Exact
+			}
+			return math.MinInt64, big.Above
+		}
+		return math.MinInt64, big.Below
+	}
+
+	u, exact := truncate(exp, frac)
+	i := int64(u.L)
+	if sign != 0 {
+		if !exact {
+			return -i, big.Above
+		}
+		return -i, big.Exact
+	}
+	if !exact {
+		return i, big.Below
+	}
+	return i, big.Exact
+}
+
+// Uint64 returns the unsigned integer resulting from truncating f towards zero.
+// If f cannot be represented in an uint64, the result is math.MaxUint64, the same as SoftFloat and TestFloat for x86:
+// (math.MaxUint64, big.Above) for the numbers less than or equal to -1 and (math.MaxUint64, big.Below) for the positive numbers.
+// If f is NaN, the result is (math.MaxUint64, big.Below).
+// The accuracy is big.Exact if the result is exactly f.
+func (f Float128) Uint64() (uint64, big.Accuracy) {
+	if f.IsNaN() {
+		return math.MaxUint64, big.Below
+	}
+
+	sign, exp, frac := f.split()
+	if exp >= 64 {
+		if sign != 0 {
+			return math.MaxUint64, big.Above
+		}
+		return math.MaxUint64, big.Below
+	}
+
+	u, exact := truncate(exp, frac)
+	i := u.L
+	if sign != 0 {
+		if i != 0 {
+			// f <= -1
+			return math.MaxUint64, big.Above
+		}
+		if !exact {
+			// -1 < f < 0
+			return 0, big.Above
+		}
+		// f is -0
+		return 0, big.Exact
+	}
+	if !exact {
+		return i, big.Below
+	}
+	return i, big.Exact
+}
+
+// FromInt128 returns the Float128 nearest to i, rounding ties to even.
+// The conversion is exact if |i| <= 2^113.
+func FromInt128(i int128.Int128) Float128 {
+	if i.H < 0 {
+		return pack(signMask128H, 0, i.Neg().Uint128(), 0)
+	}
+	return pack(0, 0, i.Uint128(), 0)
+}
+
+// FromUint128 returns the Float128 nearest to i, rounding ties to even.
+// The conversion is exact if i <= 2^113.
+func FromUint128(i int128.Uint128) Float128 {
+	return pack(0, 0, i, 0)
+}
+
+// Int128 returns the integer resulting from truncating f towards zero.
+// If f cannot be represented in an int128.Int128, the result is saturated:
+// (-2^127, big.Above) for the negative numbers and (2^127-1, big.Below) for the positive numbers.
+// If f is NaN, the result is (-2^127, big.Above).
+// The accuracy is big.Exact if the result is exactly f.
+func (f Float128) Int128() (int128.Int128, big.Accuracy) {
+	if f.IsNaN() {
+		return minInt128, big.Above
+	}
+
+	sign, exp, frac := f.split()
+	if exp >= 127 {
+		if sign != 0 {
+			if exp == 127 && frac == (int128.Uint128{H: 1 << (shift128 - 64)}) {
+				// f is exactly -2^127
+				return minInt128, big.Exact
+			}
+			return minInt128, big.Above
+		}
+		return maxInt128, big.Below
+	}
+
+	u, exact := truncate(exp, frac)
+	i := u.Int128()
+	if sign != 0 {
+		if !exact {
+			return i.Neg(), big.Above
+		}
+		return i.Neg(), big.Exact
+	}
+	if !exact {
+		return i, big.Below
+	}
+	return i, big.Exact
+}
+
+// Uint128 returns the unsigned integer resulting from truncating f towards zero.
+// If f cannot be represented in an int128.Uint128, the result is saturated:
+// (0, big.Above) for the negative numbers and (2^128-1, big.Below) for the positive numbers.
+// If f is NaN, the result is (2^128-1, big.Below).
+// The accuracy is big.Exact if the result is exactly f.
+func (f Float128) Uint128() (int128.Uint128, big.Accuracy) {
+	maxUint128 := int128.Uint128{H: math.MaxUint64, L: math.MaxUint64}
+	if f.IsNaN() {
+		return maxUint128, big.Below
+	}
+
+	sign, exp, frac := f.split()
+	if exp >= 128 {
+		if sign != 0 {
+			return int128.Uint128{}, big.Above
+		}
+		return maxUint128, big.Below
+	}
+
+	i, exact := truncate(exp, frac)
+	if sign != 0 {
+		if i != (int128.Uint128{}) || !exact {
+			// f < 0
+			return int128.Uint128{}, big.Above
+		}
+		// f is -0
+		return int128.Uint128{}, big.Exact
+	}
+	if !exact {
+		return i, big.Below
+	}
+	return i, big.Exact
+}
+
+// truncate returns the integer part of frac × 2^(exp-112).
+// exp must be less than 128.
+// exact reports whether the fractional part is zero.
+func truncate(exp int32, frac int128.Uint128) (i int128.Uint128, exact bool) {
+	if exp < 0 {
+		return int128.Uint128{}, frac.H|frac.L == 0
+	}
+	if exp >= shift128 {
+		return frac.Lsh(uint(exp - shift128)), true
+	}
+	shift := uint(shift128 - exp)
+	mask := one.Lsh(shift).Sub(one)
+	return frac.Rsh(shift), frac.And(mask) == int128.Uint128{}
+}
+
 func (f Float128) GoString() string {
 	sign := f.h & signMask128H
 	c := '+'
@@ -193,11 +480,3 @@ func (f Float128) IsNaN() bool {
 	}
 	return true
 }
-
-// a or b must be NaN.
-func propagateNaN(a, b Float128) Float128 {
-	if a.IsNaN() {
-		return a
-	}
-	return b
-}
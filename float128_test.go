@@ -1,10 +1,14 @@
 package float128
 
 import (
+	"fmt"
 	"math"
+	"math/big"
 	"math/bits"
 	"runtime"
 	"testing"
+
+	"github.com/shogo82148/int128"
 )
 
 // negZero is a float64 representation of -0.
@@ -168,6 +172,563 @@ func BenchmarkFloat64(b *testing.B) {
 	}
 }
 
+func TestFromFloat32(t *testing.T) {
+	tests := []struct {
+		input float32
+		want  Float128
+	}{
+		{0, Float128{0, 0}},
+		{float32(negZero), Float128{0x8000_0000_0000_0000, 0}},
+		{float32(math.Inf(1)), Float128{0x7fff_0000_0000_0000, 0}},
+		{float32(math.Inf(-1)), Float128{0xffff_0000_0000_0000, 0}},
+		{1, Float128{0x3fff_0000_0000_0000, 0}},
+		{-2, Float128{0xc000_0000_0000_0000, 0}},
+		{0.1, Float128{0x3ffb_9999_9a00_0000, 0}},
+
+		// small normal numbers of float32
+		{0x1p-126, Float128{0x3f81_0000_0000_0000, 0}},
+		{0x1.000002p-126, Float128{0x3f81_0000_0200_0000, 0}},
+
+		// subnormal numbers of float32
+		{0x1p-127, Float128{0x3f80_0000_0000_0000, 0}},
+		{0x1.000004p-127, Float128{0x3f80_0000_0400_0000, 0}},
+		{0x1p-149, Float128{0x3f6a_0000_0000_0000, 0}},
+	}
+
+	for _, tt := range tests {
+		got := FromFloat32(tt.input)
+		if got != tt.want {
+			t.Errorf("FromFloat32(%x) = {0x%x, 0x%x}, want {0x%x, 0x%x}", tt.input, got.h, got.l, tt.want.h, tt.want.l)
+		}
+	}
+}
+
+func TestFromFloat64_NaN(t *testing.T) {
+	tests := []struct {
+		input uint64
+		want  Float128
+	}{
+		{0x7ff8_0000_0000_0000, Float128{0x7fff_8000_0000_0000, 0}},
+		{0xfff8_0000_0000_0001, Float128{0xffff_8000_0000_0000, 0x1000_0000_0000_0000}},
+		{0x7ff0_0000_0000_0001, Float128{0x7fff_8000_0000_0000, 0x1000_0000_0000_0000}}, // signaling NaN is converted into quiet NaN
+		{0x7fff_ffff_ffff_ffff, Float128{0x7fff_ffff_ffff_ffff, 0xf000_0000_0000_0000}},
+	}
+
+	for _, tt := range tests {
+		got := FromFloat64(math.Float64frombits(tt.input))
+		if got != tt.want {
+			t.Errorf("FromFloat64(%x) = {0x%x, 0x%x}, want {0x%x, 0x%x}", tt.input, got.h, got.l, tt.want.h, tt.want.l)
+		}
+	}
+}
+
+func TestFromFloat32_NaN(t *testing.T) {
+	tests := []struct {
+		input uint32
+		want  Float128
+	}{
+		{0x7fc0_0000, Float128{0x7fff_8000_0000_0000, 0}},
+		{0xffc0_0001, Float128{0xffff_8000_0200_0000, 0}},
+		{0x7f80_0001, Float128{0x7fff_8000_0200_0000, 0}}, // signaling NaN is converted into quiet NaN
+		{0x7fff_ffff, Float128{0x7fff_ffff_fe00_0000, 0}},
+	}
+
+	for _, tt := range tests {
+		got := FromFloat32(math.Float32frombits(tt.input))
+		if got != tt.want {
+			t.Errorf("FromFloat32(%x) = {0x%x, 0x%x}, want {0x%x, 0x%x}", tt.input, got.h, got.l, tt.want.h, tt.want.l)
+		}
+	}
+}
+
+func BenchmarkFromFloat32(b *testing.B) {
+	r := newXoshiro256pp()
+	for i := 0; i < b.N; i++ {
+		f := math.Float32frombits(uint32(r.Uint64()))
+		runtime.KeepAlive(FromFloat32(f))
+	}
+}
+
+func TestFloat32(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  float32
+	}{
+		// special cases
+		{Float128{0, 0}, 0},
+		{Float128{0x8000_0000_0000_0000, 0}, float32(negZero)},
+		{Float128{0x7fff_0000_0000_0000, 0}, float32(math.Inf(1))},
+		{Float128{0xffff_0000_0000_0000, 0}, float32(math.Inf(-1))},
+
+		// normal numbers
+		{Float128{0x3fff_0000_0000_0000, 0}, 1},
+		{Float128{0xc000_0000_0000_0000, 0}, -2},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, 0.1},
+
+		// small normal numbers of float32
+		{Float128{0x3f81_0000_0000_0000, 0}, 0x1p-126},
+		{Float128{0x3f81_0000_0200_0000, 0}, 0x1.000002p-126},
+
+		// subnormal numbers of float32
+		{Float128{0x3f80_0000_0000_0000, 0}, 0x1p-127},
+		{Float128{0x3f80_0000_0200_0000, 0}, 0x1p-127},
+		{Float128{0x3f80_0000_0200_0000, 1}, 0x1.000004p-127},
+		{Float128{0x3f80_0000_0600_0000, 0}, 0x1.000008p-127},
+		{Float128{0x3f6a_0000_0000_0000, 0}, 0x1p-149},
+		{Float128{0x3f69_0000_0000_0000, 0}, 0},                                   // half of the smallest subnormal number
+		{Float128{0x3f69_0000_0000_0000, 1}, 0x1p-149},                            // above the half of the smallest subnormal number
+		{Float128{0xbf68_ffff_ffff_ffff, 0}, float32(negZero)},                    // underflow
+		{Float128{0x3f80_ffff_ffff_ffff, 0}, 0x1p-126},                            // subnormal rounds up to normal
+		{Float128{0x0000_0000_0000_0000, 1}, 0},                                   // the subnormal numbers of Float128
+		{Float128{0x8000_ffff_ffff_ffff, 0}, float32(negZero)},                    // the subnormal numbers of Float128
+		{Float128{0x407e_ffff_fe00_0000, 0}, math.MaxFloat32},                     // the largest finite number
+		{Float128{0x407e_ffff_feff_ffff, 0xffff_ffff_ffff_ffff}, math.MaxFloat32}, // below the tie
+		{Float128{0xc07e_ffff_ff00_0000, 0}, float32(math.Inf(-1))},               // overflow by rounding
+		{Float128{0x407f_0000_0000_0000, 0}, float32(math.Inf(1))},                // overflow
+
+		// round to nearest, tie to even
+		{Float128{0x3fff_0000_0100_0000, 0}, 1},
+		{Float128{0x3fff_0000_0100_0000, 1}, 0x1.000002p+00},
+		{Float128{0x3fff_0000_0300_0000, 0}, 0x1.000004p+00},
+		{Float128{0x3fff_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, 2},
+
+		// double rounding via float64 gives wrong results.
+		{Float128{0x3fff_0000_0100_0000, 0x0000_1000_0000_0000}, 0x1.000002p+00},
+	}
+
+	for _, tt := range tests {
+		got := tt.input.Float32()
+		if math.Float32bits(got) != math.Float32bits(tt.want) {
+			t.Errorf("{%x, %x}.Float32() = %x, want %x", tt.input.h, tt.input.l, got, tt.want)
+		}
+	}
+}
+
+func TestFloat32_NaN(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  uint32
+	}{
+		{Float128{0x7fff_8000_0000_0000, 0}, 0x7fc0_0000},
+		{Float128{0xffff_8000_0200_0000, 0}, 0xffc0_0001},
+		{Float128{0x7fff_0000_0200_0000, 0}, 0x7fc0_0001}, // signaling NaN is converted into quiet NaN
+		{Float128{0x7fff_0000_0000_0000, 1}, 0x7fc0_0000}, // the payload is truncated
+	}
+
+	for _, tt := range tests {
+		got := math.Float32bits(tt.input.Float32())
+		if got != tt.want {
+			t.Errorf("{%x, %x}.Float32() = %x, want %x", tt.input.h, tt.input.l, got, tt.want)
+		}
+	}
+}
+
+func TestFloat32_RoundTrip(t *testing.T) {
+	// check all float32 values.
+	// in short mode, check a part of them.
+	step := uint32(1)
+	if testing.Short() {
+		step = 65521 // the largest prime number less than 2^16
+	}
+	for hi := 0; hi < 256; hi++ {
+		hi := uint32(hi)
+		t.Run(fmt.Sprintf("%02x", hi), func(t *testing.T) {
+			t.Parallel()
+			for lo := uint32(0); lo < 1<<24; lo += step {
+				b := hi<<24 | lo
+				f := math.Float32frombits(b)
+				got := math.Float32bits(FromFloat32(f).Float32())
+				want := b
+				if f != f {
+					// NaN is converted into quiet NaN
+					want |= 1 << (shift32 - 1)
+				}
+				if got != want {
+					t.Errorf("FromFloat32(%08x).Float32() = %08x, want %08x", b, got, want)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestFloat32_Random(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 100000
+	if testing.Short() {
+		n = 1000
+	}
+	for i := 0; i < n; i++ {
+		f, _ := r.Float128Pair()
+
+		// move the exponent into around the range of float32.
+		exp := uint64(bias128 - 160 + r.Uint64()%300)
+		f.h = f.h&^(mask128<<(shift128-64)) | exp<<(shift128-64)
+
+		got := f.Float32()
+		want, _ := f.ToBigFloat().Float32()
+		if math.Float32bits(got) != math.Float32bits(want) {
+			t.Errorf("%s.Float32() = %x, want %x", dump(f), got, want)
+		}
+	}
+}
+
+func BenchmarkFloat32(b *testing.B) {
+	r := newXoshiro256pp()
+	for i := 0; i < b.N; i++ {
+		f, _ := r.Float128Pair()
+		runtime.KeepAlive(f.Float32())
+	}
+}
+
+func TestFromInt64(t *testing.T) {
+	tests := []struct {
+		input int64
+		want  Float128
+	}{
+		{0, Float128{0, 0}},
+		{1, Float128{0x3fff_0000_0000_0000, 0}},
+		{-2, Float128{0xc000_0000_0000_0000, 0}},
+		{math.MaxInt64, Float128{0x403d_ffff_ffff_ffff, 0xfffc_0000_0000_0000}},
+		{math.MinInt64, Float128{0xc03e_0000_0000_0000, 0}},
+		{1<<53 + 1, Float128{0x4034_0000_0000_0000, 0x0800_0000_0000_0000}},
+	}
+
+	for _, tt := range tests {
+		got := FromInt64(tt.input)
+		if got != tt.want {
+			t.Errorf("FromInt64(%d) = %s, want %s", tt.input, dump(got), dump(tt.want))
+		}
+	}
+}
+
+func TestFromUint64(t *testing.T) {
+	tests := []struct {
+		input uint64
+		want  Float128
+	}{
+		{0, Float128{0, 0}},
+		{1, Float128{0x3fff_0000_0000_0000, 0}},
+		{3, Float128{0x4000_8000_0000_0000, 0}},
+		{math.MaxUint64, Float128{0x403e_ffff_ffff_ffff, 0xfffe_0000_0000_0000}},
+		{1 << 63, Float128{0x403e_0000_0000_0000, 0}},
+	}
+
+	for _, tt := range tests {
+		got := FromUint64(tt.input)
+		if got != tt.want {
+			t.Errorf("FromUint64(%d) = %s, want %s", tt.input, dump(got), dump(tt.want))
+		}
+	}
+}
+
+func TestInt64(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  int64
+		acc   big.Accuracy
+	}{
+		{Float128{0, 0}, 0, big.Exact},
+		{Float128{signMask128H, 0}, 0, big.Exact},
+		{Float128{0x3fff_0000_0000_0000, 0}, 1, big.Exact},
+		{Float128{0xc000_0000_0000_0000, 0}, -2, big.Exact},
+		{Float128{0x3fff_8000_0000_0000, 0}, 1, big.Below},  // 1.5
+		{Float128{0xbfff_8000_0000_0000, 0}, -1, big.Above}, // -1.5
+		{Float128{0x3ffe_0000_0000_0000, 0}, 0, big.Below},  // 0.5
+		{Float128{0xbffe_0000_0000_0000, 0}, 0, big.Above},  // -0.5
+		{Float128{0, 1}, 0, big.Below},                      // the smallest positive subnormal number
+		{Float128{0x403d_ffff_ffff_ffff, 0xfffc_0000_0000_0000}, math.MaxInt64, big.Exact},
+		{Float128{0x403d_ffff_ffff_ffff, 0xfffe_0000_0000_0000}, math.MaxInt64, big.Below}, // 2^63 - 0.5
+		{Float128{0x403e_0000_0000_0000, 0}, math.MinInt64, big.Below},                     // 2^63
+		{Float128{0xc03e_0000_0000_0000, 0}, math.MinInt64, big.Exact},                     // -2^63
+		{Float128{0xc03e_0000_0000_0000, 0x0002_0000_0000_0000}, math.MinInt64, big.Above}, // -2^63 - 1
+		{Float128{0xc03d_ffff_ffff_ffff, 0xfffe_0000_0000_0000}, math.MinInt64 + 1, big.Above},
+		{Inf(1), math.MinInt64, big.Below},
+		{Inf(-1), math.MinInt64, big.Above},
+		{NaN(), math.MinInt64, big.Above},
+	}
+
+	for _, tt := range tests {
+		got, acc := tt.input.Int64()
+		if got != tt.want || acc != tt.acc {
+			t.Errorf("%s.Int64() = %d, %s, want %d, %s", dump(tt.input), got, acc, tt.want, tt.acc)
+		}
+	}
+}
+
+func TestUint64(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  uint64
+		acc   big.Accuracy
+	}{
+		{Float128{0, 0}, 0, big.Exact},
+		{Float128{signMask128H, 0}, 0, big.Exact},
+		{Float128{0x3fff_0000_0000_0000, 0}, 1, big.Exact},
+		{Float128{0x3fff_8000_0000_0000, 0}, 1, big.Below},              // 1.5
+		{Float128{0x3ffe_0000_0000_0000, 0}, 0, big.Below},              // 0.5
+		{Float128{0xbffe_0000_0000_0000, 0}, 0, big.Above},              // -0.5
+		{Float128{0xbfff_0000_0000_0000, 0}, math.MaxUint64, big.Above}, // -1
+		{Float128{0x403e_ffff_ffff_ffff, 0xfffe_0000_0000_0000}, math.MaxUint64, big.Exact},
+		{Float128{0x403e_ffff_ffff_ffff, 0xffff_0000_0000_0000}, math.MaxUint64, big.Below}, // 2^64 - 0.5
+		{Float128{0x403f_0000_0000_0000, 0}, math.MaxUint64, big.Below},                     // 2^64
+		{Inf(1), math.MaxUint64, big.Below},
+		{Inf(-1), math.MaxUint64, big.Above},
+		{NaN(), math.MaxUint64, big.Below},
+	}
+
+	for _, tt := range tests {
+		got, acc := tt.input.Uint64()
+		if got != tt.want || acc != tt.acc {
+			t.Errorf("%s.Uint64() = %d, %s, want %d, %s", dump(tt.input), got, acc, tt.want, tt.acc)
+		}
+	}
+}
+
+func TestInt64_Random(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 100000
+	if testing.Short() {
+		n = 1000
+	}
+	for i := 0; i < n; i++ {
+		f, _ := r.Float128Pair()
+		if f.IsNaN() {
+			continue
+		}
+
+		// move the exponent into around the range of int64.
+		exp := uint64(bias128 - 4 + r.Uint64()%72)
+		f.h = f.h&^(mask128<<(shift128-64)) | exp<<(shift128-64)
+
+		// Int64 has the same semantics as big.Float, except for the results out of range.
+		x := f.ToBigFloat()
+		got, acc := f.Int64()
+		want, wantAcc := x.Int64()
+		if x.Sign() > 0 && x.MantExp(nil) > 63 {
+			// x >= 2^63
+			want = math.MinInt64
+		}
+		if got != want || acc != wantAcc {
+			t.Errorf("%s.Int64() = %d, %s, want %d, %s", dump(f), got, acc, want, wantAcc)
+		}
+
+		// big.Float.Uint64 may report wrong accuracy, so compute the expected value from big.Float.Int.
+		var wantU uint64
+		z, wantAcc := x.Int(nil)
+		switch {
+		case z.Sign() < 0:
+			wantU, wantAcc = math.MaxUint64, big.Above
+		case x.Sign() < 0:
+			wantU, wantAcc = 0, big.Above
+		case z.IsUint64():
+			wantU = z.Uint64()
+		default:
+			wantU, wantAcc = math.MaxUint64, big.Below
+		}
+		gotU, acc := f.Uint64()
+		if gotU != wantU || acc != wantAcc {
+			t.Errorf("%s.Uint64() = %d, %s, want %d, %s", dump(f), gotU, acc, wantU, wantAcc)
+		}
+
+		// round trip
+		if acc == big.Exact {
+			if back := FromUint64(gotU); !back.Eq(f) {
+				t.Errorf("FromUint64(%d) = %s, want %s", gotU, dump(back), dump(f))
+			}
+		}
+	}
+}
+
+func BenchmarkFromInt64(b *testing.B) {
+	r := newXoshiro256pp()
+	for i := 0; i < b.N; i++ {
+		runtime.KeepAlive(FromInt64(int64(r.Uint64())))
+	}
+}
+
+func BenchmarkInt64(b *testing.B) {
+	r := newXoshiro256pp()
+	for i := 0; i < b.N; i++ {
+		f, _ := r.Float128Pair()
+		i, _ := f.Int64()
+		runtime.KeepAlive(i)
+	}
+}
+
+func TestFromInt128(t *testing.T) {
+	tests := []struct {
+		input int128.Int128
+		want  Float128
+	}{
+		{int128.Int128{}, Float128{0, 0}},
+		{int128.Int128{L: 1}, Float128{0x3fff_0000_0000_0000, 0}},
+		{int128.Int128{H: -1, L: math.MaxUint64 - 1}, Float128{0xc000_0000_0000_0000, 0}}, // -2
+		{int128.Int128{H: math.MinInt64}, Float128{0xc07e_0000_0000_0000, 0}},             // -2^127
+		{int128.Int128{H: math.MaxInt64, L: math.MaxUint64}, Float128{0x407e_0000_0000_0000, 0}},
+		{int128.Int128{H: 1 << 49, L: 1}, Float128{0x4070_0000_0000_0000, 0}},                      // 2^113 + 1, tie to even
+		{int128.Int128{H: 1 << 49, L: 3}, Float128{0x4070_0000_0000_0000, 2}},                      // 2^113 + 3, tie to even
+		{int128.Int128{H: -1 << 49, L: 1}, Float128{0xc06f_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}}, // -2^113 + 1
+	}
+
+	for _, tt := range tests {
+		got := FromInt128(tt.input)
+		if got != tt.want {
+			t.Errorf("FromInt128(%d) = %s, want %s", tt.input, dump(got), dump(tt.want))
+		}
+	}
+}
+
+func TestFromUint128(t *testing.T) {
+	tests := []struct {
+		input int128.Uint128
+		want  Float128
+	}{
+		{int128.Uint128{}, Float128{0, 0}},
+		{int128.Uint128{L: 1}, Float128{0x3fff_0000_0000_0000, 0}},
+		{int128.Uint128{H: 1 << 48, L: 1}, Float128{0x406f_0000_0000_0000, 1}}, // 2^112 + 1
+		{int128.Uint128{H: 1 << 49, L: 1}, Float128{0x4070_0000_0000_0000, 0}}, // 2^113 + 1, tie to even
+		{int128.Uint128{H: 1 << 49, L: 2}, Float128{0x4070_0000_0000_0000, 1}}, // 2^113 + 2
+		{int128.Uint128{H: 1 << 49, L: 3}, Float128{0x4070_0000_0000_0000, 2}}, // 2^113 + 3, tie to even
+		{int128.Uint128{H: math.MaxUint64, L: math.MaxUint64}, Float128{0x407f_0000_0000_0000, 0}},
+	}
+
+	for _, tt := range tests {
+		got := FromUint128(tt.input)
+		if got != tt.want {
+			t.Errorf("FromUint128(%d) = %s, want %s", tt.input, dump(got), dump(tt.want))
+		}
+	}
+}
+
+func TestInt128(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  int128.Int128
+		acc   big.Accuracy
+	}{
+		{Float128{0, 0}, int128.Int128{}, big.Exact},
+		{Float128{signMask128H, 0}, int128.Int128{}, big.Exact},
+		{Float128{0x3fff_8000_0000_0000, 0}, int128.Int128{L: 1}, big.Below},                     // 1.5
+		{Float128{0xbfff_8000_0000_0000, 0}, int128.Int128{H: -1, L: math.MaxUint64}, big.Above}, // -1.5
+		{Float128{0x406f_0000_0000_0000, 1}, int128.Int128{H: 1 << 48, L: 1}, big.Exact},         // 2^112 + 1
+		{Float128{0x407d_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, int128.Int128{H: 0x7fff_ffff_ffff_ffff, L: 0xffff_ffff_ffff_c000}, big.Exact},
+		{Float128{0x407e_0000_0000_0000, 0}, int128.Int128{H: math.MaxInt64, L: math.MaxUint64}, big.Below}, // 2^127
+		{Float128{0xc07e_0000_0000_0000, 0}, int128.Int128{H: math.MinInt64}, big.Exact},                    // -2^127
+		{Float128{0xc07e_0000_0000_0000, 1}, int128.Int128{H: math.MinInt64}, big.Above},
+		{Inf(1), int128.Int128{H: math.MaxInt64, L: math.MaxUint64}, big.Below},
+		{Inf(-1), int128.Int128{H: math.MinInt64}, big.Above},
+		{NaN(), int128.Int128{H: math.MinInt64}, big.Above},
+	}
+
+	for _, tt := range tests {
+		got, acc := tt.input.Int128()
+		if got != tt.want || acc != tt.acc {
+			t.Errorf("%s.Int128() = %d, %s, want %d, %s", dump(tt.input), got, acc, tt.want, tt.acc)
+		}
+	}
+}
+
+func TestUint128(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  int128.Uint128
+		acc   big.Accuracy
+	}{
+		{Float128{0, 0}, int128.Uint128{}, big.Exact},
+		{Float128{signMask128H, 0}, int128.Uint128{}, big.Exact},
+		{Float128{0x3fff_8000_0000_0000, 0}, int128.Uint128{L: 1}, big.Below}, // 1.5
+		{Float128{0xbffe_0000_0000_0000, 0}, int128.Uint128{}, big.Above},     // -0.5
+		{Float128{0x407e_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, int128.Uint128{H: 0xffff_ffff_ffff_ffff, L: 0xffff_ffff_ffff_8000}, big.Exact},
+		{Float128{0x407f_0000_0000_0000, 0}, int128.Uint128{H: math.MaxUint64, L: math.MaxUint64}, big.Below}, // 2^128
+		{Inf(1), int128.Uint128{H: math.MaxUint64, L: math.MaxUint64}, big.Below},
+		{Inf(-1), int128.Uint128{}, big.Above},
+		{NaN(), int128.Uint128{H: math.MaxUint64, L: math.MaxUint64}, big.Below},
+	}
+
+	for _, tt := range tests {
+		got, acc := tt.input.Uint128()
+		if got != tt.want || acc != tt.acc {
+			t.Errorf("%s.Uint128() = %d, %s, want %d, %s", dump(tt.input), got, acc, tt.want, tt.acc)
+		}
+	}
+}
+
+func TestInt128_Random(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 100000
+	if testing.Short() {
+		n = 1000
+	}
+	minInt128 := new(big.Int).Lsh(big.NewInt(-1), 127)
+	maxInt128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	maxUint128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	for i := 0; i < n; i++ {
+		f, _ := r.Float128Pair()
+		if f.IsNaN() {
+			continue
+		}
+
+		// move the exponent into around the range of int128.
+		exp := uint64(bias128 - 4 + r.Uint64()%136)
+		f.h = f.h&^(mask128<<(shift128-64)) | exp<<(shift128-64)
+
+		x := f.ToBigFloat()
+		z, acc := x.Int(nil)
+
+		// Int128
+		want, wantAcc := z, acc
+		if z.Cmp(minInt128) < 0 {
+			want, wantAcc = minInt128, big.Above
+		} else if z.Cmp(maxInt128) > 0 {
+			want, wantAcc = maxInt128, big.Below
+		}
+		got, gotAcc := f.Int128()
+		if got.String() != want.String() || gotAcc != wantAcc {
+			t.Errorf("%s.Int128() = %d, %s, want %d, %s", dump(f), got, gotAcc, want, wantAcc)
+		}
+
+		// Uint128
+		want, wantAcc = z, acc
+		if x.Sign() < 0 {
+			want, wantAcc = new(big.Int), big.Above
+		} else if z.Cmp(maxUint128) > 0 {
+			want, wantAcc = maxUint128, big.Below
+		}
+		gotU, gotAcc := f.Uint128()
+		if gotU.String() != want.String() || gotAcc != wantAcc {
+			t.Errorf("%s.Uint128() = %d, %s, want %d, %s", dump(f), gotU, gotAcc, want, wantAcc)
+		}
+	}
+}
+
+func TestFromUint128_Random(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 100000
+	if testing.Short() {
+		n = 1000
+	}
+	for i := 0; i < n; i++ {
+		u := int128.Uint128{H: r.Uint64(), L: r.Uint64()}
+		u = u.Rsh(uint(r.Uint64() % 128))
+
+		got := FromUint128(u)
+		z, _ := new(big.Int).SetString(u.String(), 10)
+		want := new(big.Float).SetPrec(shift128 + 1).SetInt(z)
+		if got.ToBigFloat().Cmp(want) != 0 {
+			t.Errorf("FromUint128(%d) = %s, want %s", u, dump(got), want.Text('p', 0))
+		}
+
+		i := u.Int128()
+		got = FromInt128(i)
+		z, _ = new(big.Int).SetString(i.String(), 10)
+		want = new(big.Float).SetPrec(shift128 + 1).SetInt(z)
+		if got.ToBigFloat().Cmp(want) != 0 {
+			t.Errorf("FromInt128(%d) = %s, want %s", i, dump(got), want.Text('p', 0))
+		}
+	}
+}
+
 func TestGoString(t *testing.T) {
 	tests := []struct {
 		input Float128
@@ -0,0 +1,711 @@
+package float128
+
+// This file implements the conversion of floating point numbers to strings,
+// which is based on the strconv package.
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/shogo82148/int128"
+)
+
+const (
+	lowerhex = "0123456789abcdef"
+	upperhex = "0123456789ABCDEF"
+)
+
+// String returns the shortest decimal representation of f
+// that round-trips through [Parse].
+// It is equivalent to FormatFloat(f, 'g', -1).
+func (f Float128) String() string {
+	return FormatFloat(f, 'g', -1)
+}
+
+// FormatFloat converts the floating-point number f to a string,
+// according to the format fmt and precision prec.
+//
+// The format fmt is one of
+// 'b' (-ddddp±ddd, a binary exponent),
+// 'e' (-d.dddde±dd, a decimal exponent),
+// 'E' (-d.ddddE±dd, a decimal exponent),
+// 'f' (-ddd.dddd, no exponent),
+// 'g' ('e' for large exponents, 'f' otherwise),
+// 'G' ('E' for large exponents, 'f' otherwise),
+// 'x' (-0x1.ffffp±ddd, a hexadecimal fraction and binary exponent), or
+// 'X' (-0X1.FFFFP±ddd, a hexadecimal fraction and binary exponent).
+//
+// The precision prec controls the number of digits (excluding the exponent)
+// printed by the 'e', 'E', 'f', 'g', 'G', 'x', and 'X' formats.
+// For 'e', 'E', 'f', 'x', and 'X', it is the number of digits after the decimal point.
+// For 'g' and 'G' it is the maximum number of significant digits (trailing
+// zeros are removed).
+// The special precision -1 uses the smallest number of digits
+// necessary such that [Parse] will return f exactly.
+func FormatFloat(f Float128, fmt byte, prec int) string {
+	return string(AppendFloat(make([]byte, 0, max(prec+4, 48)), f, fmt, prec))
+}
+
+// AppendFloat appends the string form of the floating-point number f,
+// as generated by [FormatFloat], to dst and returns the extended buffer.
+func AppendFloat(dst []byte, f Float128, fmt byte, prec int) []byte {
+	neg := f.h&signMask128H != 0
+	exp := int((f.h >> (shift128 - 64)) & mask128)
+	mant := int128.Uint128{H: f.h & fracMask128H, L: f.l}
+
+	switch exp {
+	case mask128:
+		// Inf, NaN
+		var s string
+		switch {
+		case mant.H|mant.L != 0:
+			s = "NaN"
+		case neg:
+			s = "-Inf"
+		default:
+			s = "+Inf"
+		}
+		return append(dst, s...)
+
+	case 0:
+		// denormalized
+		exp++
+
+	default:
+		// add implicit top bit
+		mant.H |= 1 << (shift128 - 64)
+	}
+	exp -= bias128
+
+	// Pick off easy binary, hex formats.
+	if fmt == 'b' {
+		return fmtB(dst, neg, mant, exp)
+	}
+	if fmt == 'x' || fmt == 'X' {
+		return fmtX(dst, prec, fmt, neg, mant, exp)
+	}
+
+	var buf [64]byte
+	var digs decimalSlice
+	shortest := prec < 0
+	if shortest {
+		digs = shortestDigits(buf[:], mant, exp)
+		// Precision for shortest representation mode.
+		switch fmt {
+		case 'e', 'E':
+			prec = digs.nd - 1
+		case 'f':
+			prec = max(digs.nd-digs.dp, 0)
+		case 'g', 'G':
+			prec = digs.nd
+		}
+	} else {
+		// Round appropriately.
+		switch fmt {
+		case 'e', 'E':
+			digs = fixedDigits(buf[:], mant, exp, prec+1, false)
+		case 'f':
+			digs = fixedDigits(buf[:], mant, exp, prec, true)
+		case 'g', 'G':
+			if prec == 0 {
+				prec = 1
+			}
+			digs = fixedDigits(buf[:], mant, exp, prec, false)
+		}
+	}
+	return formatDigits(dst, shortest, neg, digs, prec, fmt)
+}
+
+func formatDigits(dst []byte, shortest bool, neg bool, digs decimalSlice, prec int, fmt byte) []byte {
+	switch fmt {
+	case 'e', 'E':
+		return fmtE(dst, neg, digs, prec, fmt)
+	case 'f':
+		return fmtF(dst, neg, digs, prec)
+	case 'g', 'G':
+		eprec := prec
+		if eprec > digs.nd && digs.nd >= digs.dp {
+			eprec = digs.nd
+		}
+		// %e is used if the exponent from the conversion
+		// is less than -4 or greater than or equal to the precision.
+		// if precision was the shortest possible, use precision 6 for this decision.
+		if shortest {
+			eprec = 6
+		}
+		exp := digs.dp - 1
+		if exp < -4 || exp >= eprec {
+			if prec > digs.nd {
+				prec = digs.nd
+			}
+			return fmtE(dst, neg, digs, prec-1, fmt+'e'-'g')
+		}
+		if prec > digs.dp {
+			prec = digs.nd
+		}
+		return fmtF(dst, neg, digs, max(prec-digs.dp, 0))
+	}
+
+	// unknown format
+	return append(dst, '%', fmt)
+}
+
+// scaleDigits sets r = r0 × 2^(exp-shift128) / 10^k, s = s0 and m = 2^(exp-shift128) / 10^k
+// with the common denominator for the digit generation of v = mant × 2^(exp-shift128),
+// where k is the estimated decimal exponent of v.
+// It returns k, which satisfies 10^(k-1) <= v < 10^(k+1).
+func scaleDigits(r, s, m *nat, r0 int128.Uint128, s0 uint64, mant int128.Uint128, exp int) (k int) {
+	e := exp - shift128
+
+	// the exponent of the leading bit, 2^e2 <= mant × 2^e < 2^(e2+1).
+	// log10(2) × e2 never gets close to an integer enough to be miscalculated by floor,
+	// because |e2| is small enough.
+	e2 := e + mant.Len() - 1
+	k = int(math.Floor(float64(e2)*math.Log10(2))) + 1
+
+	// multiply by 2^e / 10^k = 2^(e-k) / 5^k.
+	s.setUint64(s0)
+	m.setUint64(1)
+	if k >= 0 {
+		s.mulPow5(k)
+		r.setUint128(r0)
+	} else {
+		m.mulPow5(-k)
+		r.setMul(m, r0)
+	}
+	if t := e - k; t >= 0 {
+		r.lsh(uint(t))
+		m.lsh(uint(t))
+	} else {
+		s.lsh(uint(-t))
+	}
+
+	normalizeDigits(r, s, m)
+	return
+}
+
+// normalizeDigits normalizes s for divDigit.
+// It doesn't change the ratios of r, s, and m.
+func normalizeDigits(r, s, m *nat) {
+	t := s.normShift()
+	r.lsh(t)
+	s.lsh(t)
+	m.lsh(t)
+}
+
+// shortestDigits returns the shortest decimal digits of mant × 2^(exp-shift128)
+// that will let the original floating point value be precisely reconstructed.
+// It uses the free-format algorithm of Steele & White and Burger & Dybvig
+// with fixed-size multi-precision integers.
+// The digits are stored in buf if it is large enough.
+func shortestDigits(buf []byte, mant int128.Uint128, exp int) decimalSlice {
+	// If mantissa is zero, the number is zero; stop now.
+	if mant.H|mant.L == 0 {
+		return decimalSlice{d: buf}
+	}
+
+	// Any decimal number between v - m- and v + m+ (possibly inclusive)
+	// will round to the original floating point number v.
+	// Both m- and m+ are the half of the distance to the neighbors,
+	// except that the lower neighbor is closer if mant is a power of 2 and exp is not the minimum.
+	// In that case, m+ = 2m-.
+	const minexp = 1 - bias128 // minimum possible exponent
+	boundary := mant.H == 1<<(shift128-64) && mant.L == 0 && exp > minexp
+
+	// v = r / s × 2^(exp-shift128), m- = m / s × 2^(exp-shift128).
+	var r, s, m, t nat
+	var k int
+	if boundary {
+		k = scaleDigits(&r, &s, &m, mant.Lsh(2), 4, mant, exp)
+	} else {
+		k = scaleDigits(&r, &s, &m, mant.Lsh(1), 2, mant, exp)
+	}
+
+	// The upper and lower bounds are possible outputs only if
+	// the original mantissa is even, so that IEEE round-to-even
+	// would round to the original mantissa and not the neighbors.
+	inclusive := mant.L%2 == 0
+
+	// high reports whether v + m+ reaches s.
+	high := func() bool {
+		t.add(&r, &m)
+		if boundary {
+			t.add(&t, &m)
+		}
+		c := t.cmp(&s)
+		return c > 0 || c == 0 && inclusive
+	}
+
+	// fix up the estimation of k,
+	// so that the first digit is the most significant digit of v + m+.
+	if high() {
+		k++
+		s.mulWord(10)
+		normalizeDigits(&r, &s, &m)
+	}
+
+	// Now we can figure out the minimum number of digits required.
+	// Walk along until v has distinguished itself from the upper and lower bounds.
+	digs := buf[:0]
+	for {
+		r.mulWord(10)
+		m.mulWord(10)
+		d := r.divDigit(&s)
+
+		// Okay to round down (truncate) if the rest is smaller than m-.
+		c := r.cmp(&m)
+		okdown := c < 0 || c == 0 && inclusive
+
+		// Okay to round up if the rest is larger than s - m+.
+		okup := high()
+
+		switch {
+		case !okdown && !okup:
+			digs = append(digs, '0'+d)
+			continue
+		case okdown && okup:
+			// If it's okay to do either, then round to the nearest one.
+			t.add(&r, &r)
+			if c := t.cmp(&s); c > 0 || c == 0 && d%2 == 1 {
+				d++
+			}
+		case okup:
+			d++
+		}
+		digs = append(digs, '0'+d)
+		break
+	}
+	return trimDigits(decimalSlice{d: digs, nd: len(digs), dp: k})
+}
+
+// fixedDigits returns the decimal digits of mant × 2^(exp-shift128),
+// rounded to n significant digits, or n digits after the decimal point if fixed is true.
+// It rounds to nearest, ties to even.
+// The digits are stored in buf if it is large enough.
+func fixedDigits(buf []byte, mant int128.Uint128, exp int, n int, fixed bool) decimalSlice {
+	// If mantissa is zero, the number is zero; stop now.
+	if mant.H|mant.L == 0 {
+		return decimalSlice{d: buf}
+	}
+
+	// v = r / s × 10^k
+	var r, s, m, t nat
+	k := scaleDigits(&r, &s, &m, mant, 1, mant, exp)
+
+	// fix up the estimation of k,
+	// so that the first digit is the most significant digit of v.
+	if r.cmp(&s) >= 0 {
+		k++
+		s.mulWord(10)
+		normalizeDigits(&r, &s, &m)
+	}
+	if fixed {
+		n += k
+	}
+	if n < 0 {
+		// v < 10^k <= 10^(-n-1), it is rounded to zero.
+		return decimalSlice{d: buf}
+	}
+
+	digs := buf[:0]
+	for len(digs) < n && !r.isZero() {
+		r.mulWord(10)
+		d := r.divDigit(&s)
+		digs = append(digs, '0'+d)
+	}
+
+	// round to nearest, tie to even
+	if !r.isZero() {
+		t.add(&r, &r)
+		c := t.cmp(&s)
+		odd := len(digs) > 0 && (digs[len(digs)-1]-'0')%2 == 1
+		if c > 0 || c == 0 && odd {
+			// round up
+			i := len(digs) - 1
+			for ; i >= 0; i-- {
+				if digs[i] < '9' {
+					digs[i]++
+					break
+				}
+			}
+			if i >= 0 {
+				digs = digs[:i+1]
+			} else {
+				// Number is all 9s.
+				// Change to single 1 with adjusted decimal point.
+				digs = append(digs[:0], '1')
+				k++
+			}
+		}
+	}
+	return trimDigits(decimalSlice{d: digs, nd: len(digs), dp: k})
+}
+
+// trimDigits trims trailing zeros from d.
+func trimDigits(d decimalSlice) decimalSlice {
+	for d.nd > 0 && d.d[d.nd-1] == '0' {
+		d.nd--
+	}
+	if d.nd == 0 {
+		d.dp = 0
+	}
+	return d
+}
+
+type decimalSlice struct {
+	d      []byte
+	nd, dp int
+}
+
+// %e: -d.ddddde±dd
+func fmtE(dst []byte, neg bool, d decimalSlice, prec int, fmt byte) []byte {
+	// sign
+	if neg {
+		dst = append(dst, '-')
+	}
+
+	// first digit
+	ch := byte('0')
+	if d.nd != 0 {
+		ch = d.d[0]
+	}
+	dst = append(dst, ch)
+
+	// .moredigits
+	if prec > 0 {
+		dst = append(dst, '.')
+		i := 1
+		m := min(d.nd, prec+1)
+		if i < m {
+			dst = append(dst, d.d[i:m]...)
+			i = m
+		}
+		for ; i <= prec; i++ {
+			dst = append(dst, '0')
+		}
+	}
+
+	// e±
+	dst = append(dst, fmt)
+	exp := d.dp - 1
+	if d.nd == 0 { // special case: 0 has exponent 0
+		exp = 0
+	}
+	if exp < 0 {
+		ch = '-'
+		exp = -exp
+	} else {
+		ch = '+'
+	}
+	dst = append(dst, ch)
+
+	// dd or ddd or dddd
+	if exp < 10 {
+		dst = append(dst, '0')
+	}
+	return strconv.AppendInt(dst, int64(exp), 10)
+}
+
+// %f: -ddddddd.ddddd
+func fmtF(dst []byte, neg bool, d decimalSlice, prec int) []byte {
+	// sign
+	if neg {
+		dst = append(dst, '-')
+	}
+
+	// integer, padded with zeros as needed.
+	if d.dp > 0 {
+		m := min(d.nd, d.dp)
+		dst = append(dst, d.d[:m]...)
+		for ; m < d.dp; m++ {
+			dst = append(dst, '0')
+		}
+	} else {
+		dst = append(dst, '0')
+	}
+
+	// fraction
+	if prec > 0 {
+		dst = append(dst, '.')
+		for i := 1; i <= prec; i++ {
+			ch := byte('0')
+			if j := d.dp + i - 1; 0 <= j && j < d.nd {
+				ch = d.d[j]
+			}
+			dst = append(dst, ch)
+		}
+	}
+
+	return dst
+}
+
+// %b: -ddddddddp±ddd
+func fmtB(dst []byte, neg bool, mant int128.Uint128, exp int) []byte {
+	// sign
+	if neg {
+		dst = append(dst, '-')
+	}
+
+	// mantissa
+	dst = mant.Append(dst, 10)
+
+	// p
+	dst = append(dst, 'p')
+
+	// ±exponent
+	exp -= shift128
+	if exp >= 0 {
+		dst = append(dst, '+')
+	}
+	return strconv.AppendInt(dst, int64(exp), 10)
+}
+
+// %x: -0x1.yyyyyyyyp±ddd or -0x0p+0. (y is hex digit, d is decimal digit)
+func fmtX(dst []byte, prec int, fmt byte, neg bool, mant int128.Uint128, exp int) []byte {
+	if mant.H|mant.L == 0 {
+		exp = 0
+	}
+
+	// Shift digits so leading 1 (if any) is at bit 1<<124.
+	mant = mant.Lsh(124 - shift128)
+	for mant.H|mant.L != 0 && mant.H&(1<<60) == 0 {
+		mant = mant.Lsh(1)
+		exp--
+	}
+
+	// Round if requested.
+	if prec >= 0 && prec < 28 {
+		shift := uint(prec * 4)
+		extra := mant.Lsh(shift).And(one.Lsh(124).Sub(one))
+		mant = mant.Rsh(124 - shift)
+		if extra.Or(mant.And(one)).Cmp(one.Lsh(123)) > 0 {
+			mant = mant.Add(one)
+		}
+		mant = mant.Lsh(124 - shift)
+		if mant.H&(1<<61) != 0 {
+			// Wrapped around.
+			mant = mant.Rsh(1)
+			exp++
+		}
+	}
+
+	hex := lowerhex
+	if fmt == 'X' {
+		hex = upperhex
+	}
+
+	// sign, 0x, leading digit
+	if neg {
+		dst = append(dst, '-')
+	}
+	dst = append(dst, '0', fmt, '0'+byte((mant.H>>60)&1))
+
+	// .fraction
+	mant = mant.Lsh(4) // remove leading 0 or 1
+	if prec < 0 && mant.H|mant.L != 0 {
+		dst = append(dst, '.')
+		for mant.H|mant.L != 0 {
+			dst = append(dst, hex[(mant.H>>60)&15])
+			mant = mant.Lsh(4)
+		}
+	} else if prec > 0 {
+		dst = append(dst, '.')
+		for i := 0; i < prec; i++ {
+			dst = append(dst, hex[(mant.H>>60)&15])
+			mant = mant.Lsh(4)
+		}
+	}
+
+	// p±
+	ch := byte('P')
+	if fmt == 'x' {
+		ch = 'p'
+	}
+	dst = append(dst, ch)
+	if exp < 0 {
+		ch = '-'
+		exp = -exp
+	} else {
+		ch = '+'
+	}
+	dst = append(dst, ch)
+
+	// dd or ddd or dddd or ddddd
+	if exp < 10 {
+		dst = append(dst, '0')
+	}
+	return strconv.AppendInt(dst, int64(exp), 10)
+}
+
+// Format implements [fmt.Formatter].
+// It accepts the formats 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v'
+// with the flags and the precision in the same way as float64.
+// '%#v' prints the result of [Float128.GoString].
+func (f Float128) Format(s fmt.State, verb rune) {
+	prec, hasPrec := s.Precision()
+	plus := s.Flag('+')
+	space := s.Flag(' ')
+	sharp := s.Flag('#')
+	switch verb {
+	case 'v':
+		if sharp {
+			io.WriteString(s, f.GoString())
+			return
+		}
+		// fmt ignores the plus flag for %+v of float64.
+		plus = false
+		verb = 'g'
+		if !hasPrec {
+			prec = -1
+		}
+	case 'b', 'g', 'G', 'x', 'X':
+		if !hasPrec {
+			prec = -1
+		}
+	case 'f', 'F', 'e', 'E':
+		if verb == 'F' {
+			verb = 'f'
+		}
+		if !hasPrec {
+			prec = 6
+		}
+	default:
+		fmt.Fprintf(s, "%%!%c(float128.Float128=%s)", verb, f.String())
+		return
+	}
+
+	// Format number, reserving space for leading + sign if needed.
+	var buf [64]byte
+	num := AppendFloat(buf[:1], f, byte(verb), prec)
+	if num[1] == '-' || num[1] == '+' {
+		num = num[1:]
+	} else {
+		num[0] = '+'
+	}
+	// space means to add a leading space instead of a "+" sign unless plus is used.
+	if space && num[0] == '+' && !plus {
+		num[0] = ' '
+	}
+	// Special handling for infinities and NaN,
+	// which don't look like a number so shouldn't be padded with zeros.
+	if num[1] == 'I' || num[1] == 'N' {
+		// Remove sign before NaN if not asked for.
+		if num[1] == 'N' && !space && !plus {
+			num = num[1:]
+		}
+		pad(s, num, false)
+		return
+	}
+	// The sharp flag forces printing a decimal point but removes
+	// trailing zeros for %e, %f with the sharp flag...
+	if sharp && verb != 'b' {
+		digits := 0
+		switch verb {
+		case 'g', 'G', 'x':
+			digits = prec
+			// If no precision is set explicitly use a precision of 6.
+			if digits == -1 {
+				digits = 6
+			}
+		}
+
+		// Buffer pre-allocated with enough room for
+		// exponent notations of the form "e+1234" or "p-16494".
+		var tailBuf [8]byte
+		tail := tailBuf[:0]
+
+		hasDecimalPoint := false
+		sawNonzeroDigit := false
+		// Starting from i = 1 to skip sign at num[0].
+		for i := 1; i < len(num); i++ {
+			switch num[i] {
+			case '.':
+				hasDecimalPoint = true
+			case 'p', 'P':
+				tail = append(tail, num[i:]...)
+				num = num[:i]
+			case 'e', 'E':
+				if verb != 'x' && verb != 'X' {
+					tail = append(tail, num[i:]...)
+					num = num[:i]
+					break
+				}
+				fallthrough
+			default:
+				if num[i] != '0' {
+					sawNonzeroDigit = true
+				}
+				// Count significant digits after the first non-zero digit.
+				if sawNonzeroDigit {
+					digits--
+				}
+			}
+		}
+		if !hasDecimalPoint {
+			// Leading digit 0 should contribute once to digits.
+			if len(num) == 2 && num[1] == '0' {
+				digits--
+			}
+			num = append(num, '.')
+		}
+		for digits > 0 {
+			num = append(num, '0')
+			digits--
+		}
+		num = append(num, tail...)
+	}
+	// We want a sign if asked for and if the sign is not positive.
+	if plus || num[0] != '+' {
+		// If we're zero padding to the left we want the sign before the leading zeros.
+		// Achieve this by writing the sign out and then padding the unsigned number.
+		// Zero padding is allowed only to the left.
+		if w, ok := s.Width(); ok && s.Flag('0') && !s.Flag('-') && w > len(num) {
+			s.Write(num[:1])
+			writePadding(s, w-len(num), '0')
+			s.Write(num[1:])
+			return
+		}
+		pad(s, num, s.Flag('0'))
+		return
+	}
+	// No sign to show and the number is positive; just print the unsigned number.
+	pad(s, num[1:], s.Flag('0'))
+}
+
+// pad appends b to s, padded on left (!s.Flag('-')) or right (s.Flag('-')).
+// It pads with zeros on the left if zero is true.
+func pad(s fmt.State, b []byte, zero bool) {
+	w, ok := s.Width()
+	if !ok || w <= len(b) {
+		s.Write(b)
+		return
+	}
+	if s.Flag('-') {
+		// right padding
+		s.Write(b)
+		writePadding(s, w-len(b), ' ')
+	} else if zero {
+		// left padding with zeros
+		writePadding(s, w-len(b), '0')
+		s.Write(b)
+	} else {
+		// left padding with spaces
+		writePadding(s, w-len(b), ' ')
+		s.Write(b)
+	}
+}
+
+// writePadding writes n bytes of padByte to s.
+func writePadding(s fmt.State, n int, padByte byte) {
+	var buf [16]byte
+	for i := range buf {
+		buf[i] = padByte
+	}
+	for n > len(buf) {
+		s.Write(buf[:])
+		n -= len(buf)
+	}
+	s.Write(buf[:n])
+}
@@ -0,0 +1,202 @@
+package float128
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestFormatFloat(t *testing.T) {
+	tests := []struct {
+		input Float128
+		fmt   byte
+		prec  int
+		want  string
+	}{
+		// special cases
+		{Float128{0, 0}, 'g', -1, "0"},
+		{Float128{signMask128H, 0}, 'g', -1, "-0"},
+		{Inf(1), 'g', -1, "+Inf"},
+		{Inf(-1), 'e', 5, "-Inf"},
+		{NaN(), 'f', -1, "NaN"},
+
+		// shortest
+		{Float128{0x3fff_0000_0000_0000, 0}, 'g', -1, "1"},
+		{Float128{0x3fff_0000_0000_0000, 0}, 'e', -1, "1e+00"},
+		{Float128{0x3fff_0000_0000_0000, 0}, 'f', -1, "1"},
+		{Float128{0xc000_0000_0000_0000, 0}, 'g', -1, "-2"},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, 'g', -1, "0.1"},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, 'E', -1, "1E-01"},
+		{Float128{0x4000_921f_b544_42d1, 0x8469_898c_c517_01b8}, 'g', -1, "3.1415926535897932384626433832795028"},
+		{Float128{0x4019_d6f3_4540_0000, 0}, 'g', -1, "1.23456789e+08"},
+		{Float128{0x4019_d6f3_4540_0000, 0}, 'f', -1, "123456789"},
+		{Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, 'g', -1, "1.189731495357231765085759326628007e+4932"}, // the largest finite number
+		{Float128{0x0001_0000_0000_0000, 0}, 'g', -1, "3.3621031431120935062626778173217526e-4932"},                    // the smallest positive normal number
+		{Float128{0, 1}, 'g', -1, "6e-4966"},                                                                           // the smallest positive subnormal number
+
+		// fixed precision
+		{Float128{0x3fff_8000_0000_0000, 0}, 'e', 3, "1.500e+00"},
+		{Float128{0x3fff_8000_0000_0000, 0}, 'f', 0, "2"},
+		{Float128{0x3fff_8000_0000_0000, 0}, 'G', 10, "1.5"},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, 'e', 40, "1.0000000000000000000000000000000000481482e-01"},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, 'f', 5, "0.10000"},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, 'g', 40, "0.1000000000000000000000000000000000048148"},
+		{Float128{0x4000_921f_b544_42d1, 0x8469_898c_c517_01b8}, 'g', 5, "3.1416"},
+		{Float128{0x4000_921f_b544_42d1, 0x8469_898c_c517_01b8}, 'f', 34, "3.1415926535897932384626433832795028"},
+		{Float128{0x3ff1_a36e_2eb1_c432, 0xca57_a786_c226_809d}, 'f', 3, "0.000"}, // 0.0001
+		{Float128{0x3ff8_30be_0ded_288c, 0xe703_afb7_e90f_f972}, 'f', 2, "0.01"},  // 0.0093
+		{Float128{0, 1}, 'e', 10, "6.4751751194e-4966"},
+
+		// binary exponent
+		{Float128{0x3fff_0000_0000_0000, 0}, 'b', -1, "5192296858534827628530496329220096p-112"},
+		{Float128{0, 1}, 'b', -1, "1p-16494"},
+
+		// hexadecimal
+		{Float128{0x3fff_0000_0000_0000, 0}, 'x', -1, "0x1p+00"},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, 'x', -1, "0x1.999999999999999999999999999ap-04"},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, 'X', 3, "0X1.99AP-04"},
+		{Float128{0x3fff_f800_0000_0000, 0}, 'x', 0, "0x1p+01"},
+		{Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, 'x', -1, "0x1.ffffffffffffffffffffffffffffp+16383"},
+		{Float128{0, 1}, 'x', -1, "0x1p-16494"},
+		{Float128{0, 0}, 'x', 2, "0x0.00p+00"},
+	}
+
+	for _, tt := range tests {
+		got := FormatFloat(tt.input, tt.fmt, tt.prec)
+		if got != tt.want {
+			t.Errorf("FormatFloat(%s, %q, %d) = %q, want %q", dump(tt.input), tt.fmt, tt.prec, got, tt.want)
+		}
+	}
+}
+
+func TestFormatFloat_Float64(t *testing.T) {
+	// the exact decimal representations of float64 values are same as strconv's.
+	r := newXoshiro256pp()
+	n := 10000
+	if testing.Short() {
+		n = 100
+	}
+	for i := 0; i < n; i++ {
+		f := r.Float64()
+		if math.IsNaN(f) {
+			continue
+		}
+		for _, fmt := range []byte{'e', 'f', 'g'} {
+			for _, prec := range []int{0, 1, 5, 17, 30} {
+				got := FormatFloat(FromFloat64(f), fmt, prec)
+				want := strconv.FormatFloat(f, fmt, prec, 64)
+				if got != want {
+					t.Errorf("FormatFloat(%x, %q, %d) = %q, want %q", f, fmt, prec, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestFormatFloat_RoundTrip(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 2000
+	if testing.Short() {
+		n = 100
+	}
+	for i := 0; i < n; i++ {
+		a, b := r.Float128Pair()
+		b.h &^= mask128 << (shift128 - 64) // make it subnormal
+		for _, f := range []Float128{a, b} {
+			if f.IsNaN() {
+				continue
+			}
+			for _, fmt := range []byte{'e', 'g'} {
+				s := FormatFloat(f, fmt, -1)
+				got, err := Parse(s)
+				if err != nil {
+					t.Errorf("Parse(%q) returns error: %v", s, err)
+					continue
+				}
+				if got != f {
+					t.Errorf("Parse(FormatFloat(%s, %q, -1)) = %s, want %s", dump(f), fmt, dump(got), dump(f))
+				}
+			}
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  string
+	}{
+		{Float128{0x3fff_0000_0000_0000, 0}, "1"},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, "0.1"},
+		{Float128{0x403a_bc16_d674_ec80, 0}, "1e+18"},
+		{Inf(-1), "-Inf"},
+	}
+	for _, tt := range tests {
+		got := tt.input.String()
+		if got != tt.want {
+			t.Errorf("%s.String() = %q, want %q", dump(tt.input), got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		input  Float128
+		want   string
+	}{
+		{"%v", Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, "0.1"},
+		{"%.40v", Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, "0.1000000000000000000000000000000000048148"},
+		{"%b", Float128{0x3fff_0000_0000_0000, 0}, "5192296858534827628530496329220096p-112"},
+		{"%#v", Float128{0x3fff_0000_0000_0000, 0}, "+0x1.0000000000000000000000000000p+0"},
+		{"%+.3e", Float128{0x4000_921f_b544_42d1, 0x8469_898c_c517_01b8}, "+3.142e+00"},
+		{"%040.34f", Float128{0xc000_921f_b544_42d1, 0x8469_898c_c517_01b8}, "-0003.1415926535897932384626433832795028"},
+		{"%#x", Float128{0, 1}, "0x1.0000p-16494"},
+		{"%s", Float128{0x3fff_0000_0000_0000, 0}, "%!s(float128.Float128=1)"},
+		{"%d", NaN(), "%!d(float128.Float128=NaN)"},
+	}
+	for _, tt := range tests {
+		got := fmt.Sprintf(tt.format, tt.input)
+		if got != tt.want {
+			t.Errorf("fmt.Sprintf(%q, %s) = %q, want %q", tt.format, dump(tt.input), got, tt.want)
+		}
+	}
+}
+
+func TestFormat_Float64(t *testing.T) {
+	// the shortest representations of these values are same as float64's.
+	inputs := []float64{
+		0, math.Copysign(0, -1), 1, -1, 1.5, -2.25, 100, 1e20, 1e21, 123456789, 0.0009765625, 9.5367431640625e-07,
+		math.Inf(1), math.Inf(-1), math.NaN(),
+	}
+	formats := []string{
+		"%v", "%e", "%E", "%f", "%F", "%g", "%G", "%x", "%X",
+		"%.0e", "%.3f", "%.10g", "%.2x",
+		"%10v", "%-10v|", "%010v", "%+v", "% v", "%+010.3f", "% 012e", "%-+12g|",
+		"%#v", "%#g", "%#.3g", "%#.0e", "%#.0f", "%#x", "%#.3x",
+		"%+#012.4g", "%-#14e|",
+	}
+	for _, f := range inputs {
+		for _, format := range formats {
+			if format == "%#v" {
+				// GoString is used for %#v
+				continue
+			}
+			got := fmt.Sprintf(format, FromFloat64(f))
+			want := fmt.Sprintf(format, f)
+			if got != want {
+				t.Errorf("fmt.Sprintf(%q, %v) = %q, want %q", format, f, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkAppendFloat(b *testing.B) {
+	r := newXoshiro256pp()
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		f, _ := r.Float128Pair()
+		AppendFloat(buf[:0], f, 'g', -1)
+	}
+}
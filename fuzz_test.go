@@ -0,0 +1,290 @@
+package float128
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// The fuzz targets decode the raw bit patterns into the operands,
+// and compare the results with the exact values computed by math/big and rounded by roundBig.
+// The seed corpus is in testdata/fuzz.
+
+// bigOp returns the exact result of f, or nil if the result is NaN.
+// math/big panics with big.ErrNaN for the invalid operations, e.g. Inf - Inf.
+func bigOp(f func() *big.Float) (z *big.Float) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(big.ErrNaN); !ok {
+				panic(r)
+			}
+			z = nil
+		}
+	}()
+	return f()
+}
+
+// sumPrec returns the precision that is enough to hold x + y exactly.
+func sumPrec(x, y *big.Float) uint {
+	prec := x.Prec() + y.Prec() + 2
+	if x.IsInf() || y.IsInf() || x.Sign() == 0 || y.Sign() == 0 {
+		return prec
+	}
+	d := x.MantExp(nil) - y.MantExp(nil)
+	if d < 0 {
+		d = -d
+	}
+	return prec + uint(d)
+}
+
+// withSticky adds the half of ulp to z truncated toward zero, if the truncation is inexact.
+// It keeps the correct rounding of z to the precisions that are less than the precision of z.
+func withSticky(z *big.Float, exact bool) *big.Float {
+	if exact {
+		return z
+	}
+	prec := z.Prec() + 1
+	z.SetPrec(prec)
+	half := new(big.Float).SetMantExp(big.NewFloat(1), z.MantExp(nil)-int(prec))
+	if z.Signbit() {
+		half.Neg(half)
+	}
+	return z.Add(z, half)
+}
+
+// toBig is like ToBigFloat, but returns nil for NaN.
+func toBig(f Float128) *big.Float {
+	if f.IsNaN() {
+		return nil
+	}
+	return f.ToBigFloat()
+}
+
+// checkFuzz checks got and the flags in the default rounding mode against exact.
+// exact is nil if the result should be NaN.
+func checkFuzz(t *testing.T, name string, got Float128, gotFlags Flags, exact *big.Float, args ...Float128) {
+	t.Helper()
+	if exact == nil {
+		if !got.IsNaN() {
+			t.Errorf("%s%s = %s, want NaN", name, dumpArgs(args), dump(got))
+		}
+		return
+	}
+
+	want := roundBig(exact, big.ToNearestEven)
+	if got.IsNaN() || got.ToBigFloat().Cmp(want) != 0 || (got.h&signMask128H != 0) != want.Signbit() {
+		t.Errorf("%s%s = %s, want %s", name, dumpArgs(args), dump(got), want.Text('p', 0))
+	}
+	if inexact := want.Cmp(exact) != 0; inexact != (gotFlags&FlagInexact != 0) {
+		t.Errorf("%s%s: got flags %s, want inexact = %t", name, dumpArgs(args), gotFlags, inexact)
+	}
+}
+
+func FuzzAdd(f *testing.F) {
+	f.Add(uint64(0x3fff_0000_0000_0000), uint64(0), uint64(0x3fff_0000_0000_0000), uint64(0))
+	f.Fuzz(func(t *testing.T, ah, al, bh, bl uint64) {
+		a, b := Float128{ah, al}, Float128{bh, bl}
+		var exact *big.Float
+		if x, y := toBig(a), toBig(b); x != nil && y != nil {
+			exact = bigOp(func() *big.Float { return new(big.Float).SetPrec(sumPrec(x, y)).Add(x, y) })
+		}
+		got, flags := a.AddFlags(b, ToNearestEven)
+		checkFuzz(t, "Add", a.Add(b), flags, exact, a, b)
+		checkFuzz(t, "AddFlags", got, flags, exact, a, b)
+	})
+}
+
+func FuzzMul(f *testing.F) {
+	f.Add(uint64(0x3fff_0000_0000_0000), uint64(0), uint64(0x3fff_0000_0000_0000), uint64(0))
+	f.Fuzz(func(t *testing.T, ah, al, bh, bl uint64) {
+		a, b := Float128{ah, al}, Float128{bh, bl}
+		var exact *big.Float
+		if x, y := toBig(a), toBig(b); x != nil && y != nil {
+			exact = bigOp(func() *big.Float { return new(big.Float).SetPrec(2*(shift128+1)).Mul(x, y) })
+		}
+		got, flags := a.MulFlags(b, ToNearestEven)
+		checkFuzz(t, "Mul", a.Mul(b), flags, exact, a, b)
+		checkFuzz(t, "MulFlags", got, flags, exact, a, b)
+	})
+}
+
+func FuzzQuo(f *testing.F) {
+	f.Add(uint64(0x3fff_0000_0000_0000), uint64(0), uint64(0x4000_8000_0000_0000), uint64(0))
+	f.Fuzz(func(t *testing.T, ah, al, bh, bl uint64) {
+		a, b := Float128{ah, al}, Float128{bh, bl}
+		var exact *big.Float
+		if x, y := toBig(a), toBig(b); x != nil && y != nil {
+			exact = bigOp(func() *big.Float {
+				// the quotient truncated to 1000 bits.
+				z := new(big.Float).SetPrec(1000).SetMode(big.ToZero).Quo(x, y)
+				if z.IsInf() || z.Sign() == 0 {
+					return z
+				}
+				p := new(big.Float).SetPrec(2000).Mul(z, y)
+				return withSticky(z, p.Cmp(x) == 0)
+			})
+		}
+		got, flags := a.QuoFlags(b, ToNearestEven)
+		checkFuzz(t, "Quo", a.Quo(b), flags, exact, a, b)
+		checkFuzz(t, "QuoFlags", got, flags, exact, a, b)
+	})
+}
+
+func FuzzFMA(f *testing.F) {
+	f.Add(
+		uint64(0x3fff_0000_0000_0000), uint64(0),
+		uint64(0x3fff_0000_0000_0000), uint64(0),
+		uint64(0x3fff_0000_0000_0000), uint64(0),
+	)
+	f.Fuzz(func(t *testing.T, xh, xl, yh, yl, zh, zl uint64) {
+		x, y, z := Float128{xh, xl}, Float128{yh, yl}, Float128{zh, zl}
+		var exact *big.Float
+		if bx, by, bz := toBig(x), toBig(y), toBig(z); bx != nil && by != nil && bz != nil {
+			exact = bigOp(func() *big.Float {
+				p := new(big.Float).SetPrec(2*(shift128+1)).Mul(bx, by)
+				return new(big.Float).SetPrec(sumPrec(p, bz)).Add(p, bz)
+			})
+		}
+		got, flags := FMAFlags(x, y, z, ToNearestEven)
+		checkFuzz(t, "FMA", FMA(x, y, z), flags, exact, x, y, z)
+		checkFuzz(t, "FMAFlags", got, flags, exact, x, y, z)
+	})
+}
+
+func FuzzSqrt(f *testing.F) {
+	f.Add(uint64(0x4000_0000_0000_0000), uint64(0))
+	f.Fuzz(func(t *testing.T, h, l uint64) {
+		a := Float128{h, l}
+		var exact *big.Float
+		if x := toBig(a); x != nil {
+			exact = bigOp(func() *big.Float {
+				// the square root truncated to 1000 bits.
+				z := new(big.Float).SetPrec(1000).SetMode(big.ToZero).Sqrt(x)
+				p := new(big.Float).SetPrec(2000).Mul(z, z)
+				return withSticky(z, p.Cmp(x) == 0)
+			})
+		}
+		got, flags := a.SqrtFlags(ToNearestEven)
+		checkFuzz(t, "Sqrt", a.Sqrt(), flags, exact, a)
+		checkFuzz(t, "SqrtFlags", got, flags, exact, a)
+	})
+}
+
+// bigRemainder returns the exact x - n×y, where n is x/y rounded to an integer,
+// to nearest even if nearest is true, toward zero otherwise.
+// x and y must be finite, and y must not be zero.
+func bigRemainder(x, y *big.Float, nearest bool) *big.Float {
+	if x.Sign() == 0 {
+		return x
+	}
+
+	// |x| = mx × 2^e and |y| = my × 2^e for the integers mx and my.
+	mx, ex := bigMantExp(x)
+	my, ey := bigMantExp(y)
+	e := min(ex, ey)
+	mx.Lsh(mx, uint(ex-e))
+	my.Lsh(my, uint(ey-e))
+
+	q, r := new(big.Int).QuoRem(mx, my, new(big.Int))
+	if nearest {
+		c := new(big.Int).Lsh(r, 1).Cmp(my)
+		if c > 0 || c == 0 && q.Bit(0) != 0 {
+			r.Sub(r, my)
+		}
+	}
+
+	z := new(big.Float).SetInt(r)
+	z.SetMantExp(z, e)
+	if x.Signbit() {
+		z.Neg(z)
+	}
+	return z
+}
+
+// bigMantExp returns the integer m and the exponent e such that |x| = m × 2^e.
+func bigMantExp(x *big.Float) (*big.Int, int) {
+	m := new(big.Float)
+	e := x.MantExp(m)
+	prec := int(m.MinPrec())
+	m.SetMantExp(m, prec).Abs(m)
+	i, _ := m.Int(nil)
+	return i, e - prec
+}
+
+func FuzzRemainder(f *testing.F) {
+	f.Add(uint64(0x4001_4000_0000_0000), uint64(0), uint64(0x4000_8000_0000_0000), uint64(0))
+	f.Fuzz(func(t *testing.T, ah, al, bh, bl uint64) {
+		a, b := Float128{ah, al}, Float128{bh, bl}
+		var exact, exactMod *big.Float
+		if x, y := toBig(a), toBig(b); x != nil && y != nil && !x.IsInf() && y.Sign() != 0 {
+			if y.IsInf() {
+				exact, exactMod = x, x
+			} else {
+				exact, exactMod = bigRemainder(x, y, true), bigRemainder(x, y, false)
+			}
+		}
+		got, flags := a.RemainderFlags(b)
+		checkFuzz(t, "Remainder", a.Remainder(b), flags, exact, a, b)
+		checkFuzz(t, "RemainderFlags", got, flags, exact, a, b)
+		got, _ = a.RemQuo(b)
+		checkFuzz(t, "RemQuo", got, flags, exact, a, b)
+		checkFuzz(t, "Mod", a.Mod(b), 0, exactMod, a, b)
+	})
+}
+
+func FuzzFloat64(f *testing.F) {
+	f.Add(uint64(0x3fff_0000_0000_0000), uint64(0))
+	f.Fuzz(func(t *testing.T, h, l uint64) {
+		a := Float128{h, l}
+		got := a.Float64()
+		got2, flags := a.Float64Flags(ToNearestEven)
+		if a.IsNaN() {
+			if !math.IsNaN(got) || !math.IsNaN(got2) {
+				t.Errorf("Float64(%s) = %x, %x, want NaN", dump(a), got, got2)
+			}
+			return
+		}
+
+		var want float64
+		var wantFlags Flags
+		if a.IsInf(0) {
+			want = math.Inf(int(int64(h)>>63 | 1))
+		} else {
+			want, wantFlags = float64Oracle(a.ToBigFloat(), big.ToNearestEven, false)
+		}
+		if math.Float64bits(got) != math.Float64bits(want) {
+			t.Errorf("Float64(%s) = %x, want %x", dump(a), got, want)
+		}
+		if math.Float64bits(got2) != math.Float64bits(want) || flags != wantFlags {
+			t.Errorf("Float64Flags(%s) = %x, %s, want %x, %s", dump(a), got2, flags, want, wantFlags)
+		}
+	})
+}
+
+func FuzzFromFloat64(f *testing.F) {
+	f.Add(math.Float64bits(1))
+	f.Fuzz(func(t *testing.T, bits uint64) {
+		x := math.Float64frombits(bits)
+		got := FromFloat64(x)
+		got2, flags := FromFloat64Flags(x)
+		if math.IsNaN(x) {
+			if !got.IsNaN() || !got2.IsNaN() {
+				t.Errorf("FromFloat64(%x) = %s, %s, want NaN", x, dump(got), dump(got2))
+			}
+			return
+		}
+
+		// the conversion is always exact.
+		exact := new(big.Float).SetFloat64(x)
+		checkFuzz(t, "FromFloat64", got, 0, exact)
+		checkFuzz(t, "FromFloat64Flags", got2, flags, exact)
+		if flags != 0 {
+			t.Errorf("FromFloat64Flags(%x): got flags %s, want 0", x, flags)
+		}
+
+		// the round trip must be the identity.
+		if back := got.Float64(); math.Float64bits(back) != bits {
+			t.Errorf("FromFloat64(%x).Float64() = %x", x, back)
+		}
+	})
+}
@@ -41,6 +41,20 @@ func main() {
 		f128_mulAdd()
 	case "f128_sqrt":
 		f128_sqrt()
+	case "f128_rem":
+		f128_rem()
+	case "f128_roundToInt":
+		f128_roundToInt(parseRoundingOptions(os.Args[2:]))
+	case "i64_to_f128":
+		i64_to_f128()
+	case "ui64_to_f128":
+		ui64_to_f128()
+	case "f128_to_i64_r_minMag":
+		_, exact := parseRoundingOptions(os.Args[2:])
+		f128_to_i64_r_minMag(exact)
+	case "f128_to_ui64_r_minMag":
+		_, exact := parseRoundingOptions(os.Args[2:])
+		f128_to_ui64_r_minMag(exact)
 	}
 }
 
@@ -65,13 +79,22 @@ func f128_to_f64() {
 			log.Fatal(err)
 		}
 
+		// the exception flags.
+		flags, err := parseFlags(line)
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		// test converting
 		got := math.Float64bits(f128.Float64())
-		if got != f64 {
-			fmt.Printf("%s %s %016x\n", s128, s64, got)
+		f, gotFlags := f128.Float64Flags(float128.ToNearestEven)
+		if math.IsNaN(f) && math.IsNaN(math.Float64frombits(f64)) && gotFlags == flags {
+			continue
+		}
+		if got != f64 || math.Float64bits(f) != f64 || gotFlags != flags {
+			fmt.Printf("%s %s %v %016x %016x %v\n", s128, s64, flags, got, math.Float64bits(f), gotFlags)
 			failed++
 		}
-		_ = line
 	}
 	if failed > 0 {
 		fmt.Printf("%d tests failed\n", failed)
@@ -101,18 +124,22 @@ func f64_to_f128() {
 			log.Fatal(err)
 		}
 
+		// the exception flags.
+		flags, err := parseFlags(line)
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		// test converting
-		h0, l0 := f128.Bits()
 		got := float128.FromFloat64(f64)
-		h1, l1 := got.Bits()
-		if got.IsNaN() && f128.IsNaN() {
+		_, gotFlags := float128.FromFloat64Flags(f64)
+		if got.IsNaN() && f128.IsNaN() && gotFlags == flags {
 			continue
 		}
-		if h0 != h1 || l0 != l1 {
-			fmt.Printf("%s %s %016x%016x\n", s64, s128, h1, l1)
+		if got != f128 || gotFlags != flags {
+			fmt.Printf("%s %s %v %s %v\n", s64, s128, flags, dump(got), gotFlags)
 			failed++
 		}
-		_ = line
 	}
 	if failed > 0 {
 		fmt.Printf("%d tests failed\n", failed)
@@ -127,17 +154,18 @@ func f128_mul() {
 		line := s.Text()
 		line = strings.TrimSpace(line)
 
-		a, b, c, err := parseFloat128x3(line)
+		a, b, c, flags, err := parseFloat128x3(line)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		got := a.Mul(b)
-		if got.IsNaN() && c.IsNaN() {
+		f, gotFlags := a.MulFlags(b, float128.ToNearestEven)
+		if got.IsNaN() && f.IsNaN() && c.IsNaN() && gotFlags == flags {
 			continue
 		}
-		if got != c {
-			fmt.Printf("%s %s %s %s\n", dump(a), dump(b), dump(c), dump(got))
+		if got != c || f != c || gotFlags != flags {
+			fmt.Printf("%s %s %s %v %s %s %v\n", dump(a), dump(b), dump(c), flags, dump(got), dump(f), gotFlags)
 			failed++
 		}
 	}
@@ -154,17 +182,18 @@ func f128_div() {
 		line := s.Text()
 		line = strings.TrimSpace(line)
 
-		a, b, c, err := parseFloat128x3(line)
+		a, b, c, flags, err := parseFloat128x3(line)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		got := a.Quo(b)
-		if got.IsNaN() && c.IsNaN() {
+		f, gotFlags := a.QuoFlags(b, float128.ToNearestEven)
+		if got.IsNaN() && f.IsNaN() && c.IsNaN() && gotFlags == flags {
 			continue
 		}
-		if got != c {
-			fmt.Printf("%s %s %s %s\n", dump(a), dump(b), dump(c), dump(got))
+		if got != c || f != c || gotFlags != flags {
+			fmt.Printf("%s %s %s %v %s %s %v\n", dump(a), dump(b), dump(c), flags, dump(got), dump(f), gotFlags)
 			failed++
 		}
 	}
@@ -181,17 +210,18 @@ func f128_add() {
 		line := s.Text()
 		line = strings.TrimSpace(line)
 
-		a, b, c, err := parseFloat128x3(line)
+		a, b, c, flags, err := parseFloat128x3(line)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		got := a.Add(b)
-		if got.IsNaN() && c.IsNaN() {
+		f, gotFlags := a.AddFlags(b, float128.ToNearestEven)
+		if got.IsNaN() && f.IsNaN() && c.IsNaN() && gotFlags == flags {
 			continue
 		}
-		if got != c {
-			fmt.Printf("%s %s %s %s\n", dump(a), dump(b), dump(c), dump(got))
+		if got != c || f != c || gotFlags != flags {
+			fmt.Printf("%s %s %s %v %s %s %v\n", dump(a), dump(b), dump(c), flags, dump(got), dump(f), gotFlags)
 			failed++
 		}
 	}
@@ -208,14 +238,15 @@ func f128_eq() {
 		line := s.Text()
 		line = strings.TrimSpace(line)
 
-		a, b, c, err := parseFloat128x2Bool(line)
+		a, b, c, flags, err := parseFloat128x2Bool(line)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		got := a.Eq(b)
-		if got != c {
-			fmt.Printf("%s %s %t %t\n", dump(a), dump(b), c, got)
+		_, gotFlags := a.EqFlags(b)
+		if got != c || gotFlags != flags {
+			fmt.Printf("%s %s %t %v %t %v\n", dump(a), dump(b), c, flags, got, gotFlags)
 			failed++
 		}
 	}
@@ -232,14 +263,15 @@ func f128_le() {
 		line := s.Text()
 		line = strings.TrimSpace(line)
 
-		a, b, c, err := parseFloat128x2Bool(line)
+		a, b, c, flags, err := parseFloat128x2Bool(line)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		got := a.Le(b)
-		if got != c {
-			fmt.Printf("%s %s %t %t\n", dump(a), dump(b), c, got)
+		_, gotFlags := a.LeFlags(b)
+		if got != c || gotFlags != flags {
+			fmt.Printf("%s %s %t %v %t %v\n", dump(a), dump(b), c, flags, got, gotFlags)
 			failed++
 		}
 	}
@@ -256,14 +288,15 @@ func f128_lt() {
 		line := s.Text()
 		line = strings.TrimSpace(line)
 
-		a, b, c, err := parseFloat128x2Bool(line)
+		a, b, c, flags, err := parseFloat128x2Bool(line)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		got := a.Lt(b)
-		if got != c {
-			fmt.Printf("%s %s %t %t\n", dump(a), dump(b), c, got)
+		_, gotFlags := a.LtFlags(b)
+		if got != c || gotFlags != flags {
+			fmt.Printf("%s %s %t %v %t %v\n", dump(a), dump(b), c, flags, got, gotFlags)
 			failed++
 		}
 	}
@@ -280,17 +313,18 @@ func f128_mulAdd() {
 		line := s.Text()
 		line = strings.TrimSpace(line)
 
-		a, b, c, d, err := parseFloat128x4(line)
+		a, b, c, d, flags, err := parseFloat128x4(line)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		got := float128.FMA(a, b, c)
-		if got.IsNaN() && d.IsNaN() {
+		f, gotFlags := float128.FMAFlags(a, b, c, float128.ToNearestEven)
+		if got.IsNaN() && f.IsNaN() && d.IsNaN() && gotFlags == flags {
 			continue
 		}
-		if got != d {
-			fmt.Printf("%s %s %s %s %s\n", dump(a), dump(b), dump(c), dump(d), dump(got))
+		if got != d || f != d || gotFlags != flags {
+			fmt.Printf("%s %s %s %s %v %s %s %v\n", dump(a), dump(b), dump(c), dump(d), flags, dump(got), dump(f), gotFlags)
 			failed++
 		}
 	}
@@ -307,17 +341,89 @@ func f128_sqrt() {
 		line := s.Text()
 		line = strings.TrimSpace(line)
 
-		a, b, err := parseFloat128x2(line)
+		a, b, flags, err := parseFloat128x2(line)
 		if err != nil {
 			log.Fatal(err)
 		}
 
 		got := a.Sqrt()
-		if got.IsNaN() && b.IsNaN() {
+		f, gotFlags := a.SqrtFlags(float128.ToNearestEven)
+		if got.IsNaN() && f.IsNaN() && b.IsNaN() && gotFlags == flags {
+			continue
+		}
+		if got != b || f != b || gotFlags != flags {
+			fmt.Printf("%s %s %v %s %s %v\n", dump(a), dump(b), flags, dump(got), dump(f), gotFlags)
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("%d tests failed\n", failed)
+		os.Exit(1)
+	}
+}
+
+func f128_rem() {
+	var failed int64
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		line := s.Text()
+		line = strings.TrimSpace(line)
+
+		a, b, c, flags, err := parseFloat128x3(line)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		got := a.Remainder(b)
+		f, gotFlags := a.RemainderFlags(b)
+		if got.IsNaN() && f.IsNaN() && c.IsNaN() && gotFlags == flags {
+			continue
+		}
+		if got != c || f != c || gotFlags != flags {
+			fmt.Printf("%s %s %s %v %s %s %v\n", dump(a), dump(b), dump(c), flags, dump(got), dump(f), gotFlags)
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("%d tests failed\n", failed)
+		os.Exit(1)
+	}
+}
+
+func f128_roundToInt(mode float128.RoundingMode, exact bool) {
+	// the methods which round in the fixed rounding mode.
+	methods := map[float128.RoundingMode]func(float128.Float128) float128.Float128{
+		float128.ToNearestEven: float128.Float128.RoundToEven,
+		float128.ToNearestAway: float128.Float128.Round,
+		float128.ToZero:        float128.Float128.Trunc,
+		float128.ToNegativeInf: float128.Float128.Floor,
+		float128.ToPositiveInf: float128.Float128.Ceil,
+	}
+
+	var failed int64
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		line := s.Text()
+		line = strings.TrimSpace(line)
+
+		a, b, flags, err := parseFloat128x2(line)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		f, gotFlags := a.RoundToIntFlags(mode)
+		if !exact {
+			gotFlags &^= float128.FlagInexact
+		}
+		got := f
+		if method, ok := methods[mode]; ok {
+			got = method(a)
+		}
+		if got.IsNaN() && f.IsNaN() && b.IsNaN() && gotFlags == flags {
 			continue
 		}
-		if got != b {
-			fmt.Printf("%s %s %s\n", dump(a), dump(b), dump(got))
+		if got != b || f != b || gotFlags != flags {
+			fmt.Printf("%s %s %v %s %s %v\n", dump(a), dump(b), flags, dump(got), dump(f), gotFlags)
 			failed++
 		}
 	}
@@ -327,6 +433,224 @@ func f128_sqrt() {
 	}
 }
 
+func i64_to_f128() {
+	var failed int64
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		line := s.Text()
+		line = strings.TrimSpace(line)
+
+		// the input is a 64-bit signed integer.
+		s64, line, _ := strings.Cut(line, " ")
+		i64, err := strconv.ParseUint(s64, 16, 64)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// the output is a 128-bit floating point number.
+		s128, line, _ := strings.Cut(line, " ")
+		f128, err := parseFloat128(s128)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// the exception flags.
+		flags, err := parseFlags(line)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// test converting, the conversion is always exact.
+		got := float128.FromInt64(int64(i64))
+		if got != f128 || flags != 0 {
+			fmt.Printf("%s %s %v %s\n", s64, s128, flags, dump(got))
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("%d tests failed\n", failed)
+		os.Exit(1)
+	}
+}
+
+func ui64_to_f128() {
+	var failed int64
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		line := s.Text()
+		line = strings.TrimSpace(line)
+
+		// the input is a 64-bit unsigned integer.
+		s64, line, _ := strings.Cut(line, " ")
+		u64, err := strconv.ParseUint(s64, 16, 64)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// the output is a 128-bit floating point number.
+		s128, line, _ := strings.Cut(line, " ")
+		f128, err := parseFloat128(s128)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// the exception flags.
+		flags, err := parseFlags(line)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// test converting, the conversion is always exact.
+		got := float128.FromUint64(u64)
+		if got != f128 || flags != 0 {
+			fmt.Printf("%s %s %v %s\n", s64, s128, flags, dump(got))
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("%d tests failed\n", failed)
+		os.Exit(1)
+	}
+}
+
+func f128_to_i64_r_minMag(exact bool) {
+	var failed int64
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		line := s.Text()
+		line = strings.TrimSpace(line)
+
+		// the input is a 128-bit floating point number.
+		s128, line, _ := strings.Cut(line, " ")
+		f128, err := parseFloat128(s128)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// the output is a 64-bit signed integer.
+		s64, line, _ := strings.Cut(line, " ")
+		i64, err := strconv.ParseUint(s64, 16, 64)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// the exception flags.
+		flags, err := parseFlags(line)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// test converting
+		got, _ := f128.Int64()
+		_, gotFlags := f128.Int64Flags()
+		if !exact {
+			gotFlags &^= float128.FlagInexact
+		}
+		if gotFlags != flags {
+			fmt.Printf("%s %s %v %016x %v\n", s128, s64, flags, uint64(got), gotFlags)
+			failed++
+			continue
+		}
+		if uint64(got) != i64 {
+			fmt.Printf("%s %s %v %016x %v\n", s128, s64, flags, uint64(got), gotFlags)
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("%d tests failed\n", failed)
+		os.Exit(1)
+	}
+}
+
+func f128_to_ui64_r_minMag(exact bool) {
+	var failed int64
+	s := bufio.NewScanner(os.Stdin)
+	for s.Scan() {
+		line := s.Text()
+		line = strings.TrimSpace(line)
+
+		// the input is a 128-bit floating point number.
+		s128, line, _ := strings.Cut(line, " ")
+		f128, err := parseFloat128(s128)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// the output is a 64-bit unsigned integer.
+		s64, line, _ := strings.Cut(line, " ")
+		u64, err := strconv.ParseUint(s64, 16, 64)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// the exception flags.
+		flags, err := parseFlags(line)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// test converting
+		got, _ := f128.Uint64()
+		_, gotFlags := f128.Uint64Flags()
+		if !exact {
+			gotFlags &^= float128.FlagInexact
+		}
+		if gotFlags != flags {
+			fmt.Printf("%s %s %v %016x %v\n", s128, s64, flags, got, gotFlags)
+			failed++
+			continue
+		}
+		if got != u64 {
+			fmt.Printf("%s %s %v %016x %v\n", s128, s64, flags, got, gotFlags)
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("%d tests failed\n", failed)
+		os.Exit(1)
+	}
+}
+
+// parseRoundingOptions parses the options of testfloat_gen for the rounding mode and the exactness.
+// The defaults are the same as testfloat_gen, -rnear_even and -notexact.
+func parseRoundingOptions(args []string) (mode float128.RoundingMode, exact bool) {
+	mode = float128.ToNearestEven
+	for _, arg := range args {
+		switch arg {
+		case "-rnear_even":
+			mode = float128.ToNearestEven
+		case "-rnear_maxMag":
+			mode = float128.ToNearestAway
+		case "-rminMag":
+			mode = float128.ToZero
+		case "-rmin":
+			mode = float128.ToNegativeInf
+		case "-rmax":
+			mode = float128.ToPositiveInf
+		case "-rodd":
+			mode = float128.ToOdd
+		case "-exact":
+			exact = true
+		case "-notexact":
+			exact = false
+		default:
+			log.Fatalf("unknown option: %s", arg)
+		}
+	}
+	return
+}
+
+// parseFlags parses the exception flags of testfloat.
+// The values of the flags are the same as float128.Flags.
+func parseFlags(s string) (float128.Flags, error) {
+	s, _, _ = strings.Cut(s, " ")
+	flags, err := strconv.ParseUint(s, 16, 8)
+	if err != nil {
+		return 0, err
+	}
+	return float128.Flags(flags), nil
+}
+
 func parseFloat128(s string) (float128.Float128, error) {
 	if len(s) != 32 {
 		return float128.Float128{}, fmt.Errorf("invalid length: %d", len(s))
@@ -343,10 +667,9 @@ func parseFloat128(s string) (float128.Float128, error) {
 	return float128.FromBits(h, l), nil
 }
 
-func parseFloat128x2(s string) (a, b float128.Float128, err error) {
+func parseFloat128x2(s string) (a, b float128.Float128, flags float128.Flags, err error) {
 	sa, s, _ := strings.Cut(s, " ")
 	sb, s, _ := strings.Cut(s, " ")
-	_ = s
 	a, err = parseFloat128(sa)
 	if err != nil {
 		return
@@ -355,14 +678,17 @@ func parseFloat128x2(s string) (a, b float128.Float128, err error) {
 	if err != nil {
 		return
 	}
+	flags, err = parseFlags(s)
+	if err != nil {
+		return
+	}
 	return
 }
 
-func parseFloat128x3(s string) (a, b, c float128.Float128, err error) {
+func parseFloat128x3(s string) (a, b, c float128.Float128, flags float128.Flags, err error) {
 	sa, s, _ := strings.Cut(s, " ")
 	sb, s, _ := strings.Cut(s, " ")
 	sc, s, _ := strings.Cut(s, " ")
-	_ = s
 	a, err = parseFloat128(sa)
 	if err != nil {
 		return
@@ -375,15 +701,18 @@ func parseFloat128x3(s string) (a, b, c float128.Float128, err error) {
 	if err != nil {
 		return
 	}
+	flags, err = parseFlags(s)
+	if err != nil {
+		return
+	}
 	return
 }
 
-func parseFloat128x4(s string) (a, b, c, d float128.Float128, err error) {
+func parseFloat128x4(s string) (a, b, c, d float128.Float128, flags float128.Flags, err error) {
 	sa, s, _ := strings.Cut(s, " ")
 	sb, s, _ := strings.Cut(s, " ")
 	sc, s, _ := strings.Cut(s, " ")
 	sd, s, _ := strings.Cut(s, " ")
-	_ = s
 	a, err = parseFloat128(sa)
 	if err != nil {
 		return
@@ -400,14 +729,17 @@ func parseFloat128x4(s string) (a, b, c, d float128.Float128, err error) {
 	if err != nil {
 		return
 	}
+	flags, err = parseFlags(s)
+	if err != nil {
+		return
+	}
 	return
 }
 
-func parseFloat128x2Bool(s string) (a, b float128.Float128, c bool, err error) {
+func parseFloat128x2Bool(s string) (a, b float128.Float128, c bool, flags float128.Flags, err error) {
 	sa, s, _ := strings.Cut(s, " ")
 	sb, s, _ := strings.Cut(s, " ")
 	sc, s, _ := strings.Cut(s, " ")
-	_ = s
 	a, err = parseFloat128(sa)
 	if err != nil {
 		return
@@ -417,6 +749,10 @@ func parseFloat128x2Bool(s string) (a, b float128.Float128, c bool, err error) {
 		return
 	}
 	c = sc != "0"
+	flags, err = parseFlags(s)
+	if err != nil {
+		return
+	}
 	return
 }
 
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+const (
+	prec128 = 113
+	bias128 = 16383
+	prec64  = 53
+	bias64  = 1023
+)
+
+// the exception flags of SoftFloat.
+const (
+	flagInexact   = 0x01
+	flagUnderflow = 0x02
+	flagOverflow  = 0x04
+	flagInfinite  = 0x08
+	flagInvalid   = 0x10
+)
+
+// f128 is the binary representation of a quadruple precision floating point number.
+type f128 struct {
+	h, l uint64
+}
+
+// defaultNaN128 is the NaN returned by the invalid operations.
+var defaultNaN128 = f128{h: 0x7fff_8000_0000_0000}
+
+func (f f128) String() string {
+	return fmt.Sprintf("%016X%016X", f.h, f.l)
+}
+
+func (f f128) signbit() bool {
+	return f.h>>63 != 0
+}
+
+func (f f128) exp() uint64 {
+	return (f.h >> 48) & 0x7fff
+}
+
+func (f f128) isNaN() bool {
+	return f.exp() == 0x7fff && (f.h&(1<<48-1)|f.l) != 0
+}
+
+func (f f128) isSignalingNaN() bool {
+	return f.isNaN() && f.h&(1<<47) == 0
+}
+
+func (f f128) isInf() bool {
+	return f.exp() == 0x7fff && (f.h&(1<<48-1)|f.l) == 0
+}
+
+func (f f128) isFinite() bool {
+	return f.exp() != 0x7fff
+}
+
+func (f f128) isZero() bool {
+	return (f.h&^(1<<63) | f.l) == 0
+}
+
+func (f f128) neg() f128 {
+	return f128{f.h ^ 1<<63, f.l}
+}
+
+func (f f128) quiet() f128 {
+	return f128{f.h | 1<<47, f.l}
+}
+
+// big returns the exact value of f. f must not be NaN.
+func (f f128) big() *big.Float {
+	z := new(big.Float).SetPrec(prec128)
+	switch {
+	case f.isNaN():
+		panic(big.ErrNaN{})
+	case f.isInf():
+		return z.SetInf(f.signbit())
+	}
+
+	mant, exp := f.mantExp()
+	z.SetInt(mant)
+	z.SetMantExp(z, exp)
+	if f.signbit() {
+		z.Neg(z)
+	}
+	return z
+}
+
+// mantExp returns the integer significand and the exponent such that |f| = mant × 2^exp.
+// f must be finite.
+func (f f128) mantExp() (mant *big.Int, exp int) {
+	exp = int(f.exp())
+	mant = new(big.Int).SetUint64(f.h & (1<<48 - 1))
+	mant.Lsh(mant, 64).Or(mant, new(big.Int).SetUint64(f.l))
+	if exp == 0 {
+		exp = 1
+	} else {
+		mant.SetBit(mant, prec128-1, 1)
+	}
+	return mant, exp - bias128 - (prec128 - 1)
+}
+
+// round128 returns x rounded to nearest even in the quadruple precision, and the exception flags.
+func round128(x *big.Float) (f128, uint8) {
+	bits, flags := binary128.round(x)
+	var buf [16]byte
+	bits.FillBytes(buf[:])
+	return f128{binary.BigEndian.Uint64(buf[:8]), binary.BigEndian.Uint64(buf[8:])}, flags
+}
+
+// f64 is the binary representation of a double precision floating point number.
+type f64 uint64
+
+func (f f64) String() string {
+	return fmt.Sprintf("%016X", uint64(f))
+}
+
+func (f f64) isNaN() bool {
+	return math.IsNaN(math.Float64frombits(uint64(f)))
+}
+
+func (f f64) isSignalingNaN() bool {
+	return f.isNaN() && f&(1<<51) == 0
+}
+
+// big returns the exact value of f. f must not be NaN.
+func (f f64) big() *big.Float {
+	return new(big.Float).SetFloat64(math.Float64frombits(uint64(f)))
+}
+
+// round64 returns x rounded to nearest even in the double precision, and the exception flags.
+func round64(x *big.Float) (f64, uint8) {
+	bits, flags := binary64.round(x)
+	return f64(bits.Uint64()), flags
+}
+
+// format is a binary interchange format of IEEE 754.
+type format struct {
+	prec  uint // the precision in bits, including the implicit leading bit
+	emax  int  // the maximum exponent, which is equal to the bias
+	width uint // the width of the format in bits
+}
+
+var (
+	binary64  = format{prec: prec64, emax: bias64, width: 64}
+	binary128 = format{prec: prec128, emax: bias128, width: 128}
+)
+
+// round returns the binary representation of x rounded to nearest even, and the exception flags.
+// The tininess is detected after rounding.
+func (ft format) round(x *big.Float) (*big.Int, uint8) {
+	var bits *big.Int
+	var flags uint8
+	abs := new(big.Float).Abs(x)
+	switch {
+	case x.IsInf():
+		bits = ft.inf()
+	case x.Sign() == 0:
+		bits = new(big.Int)
+	default:
+		emin := 1 - ft.emax
+		minExp := emin - int(ft.prec) + 1 // the exponent of the smallest subnormal number
+
+		// the precision is reduced in the subnormal range.
+		e := abs.MantExp(nil) - 1
+		prec := min(int(ft.prec), e-minExp+1)
+		z := new(big.Float)
+		if prec > 0 {
+			z.SetPrec(uint(prec)).Set(abs)
+		} else {
+			// abs is less than the smallest subnormal number.
+			// it is rounded to the smallest subnormal number only if it is above the half of it.
+			half := new(big.Float).SetMantExp(big.NewFloat(1), minExp-1)
+			if abs.Cmp(half) > 0 {
+				z.SetMantExp(big.NewFloat(1), minExp)
+			}
+		}
+
+		if z.Cmp(abs) != 0 {
+			flags |= flagInexact
+
+			// the tininess is detected after rounding with unbounded exponent range.
+			unbounded := new(big.Float).SetPrec(ft.prec).Set(abs)
+			if unbounded.MantExp(nil)-1 < emin {
+				flags |= flagUnderflow
+			}
+		}
+
+		if z.Sign() != 0 && z.MantExp(nil)-1 > ft.emax {
+			flags |= flagOverflow | flagInexact
+			bits = ft.inf()
+		} else {
+			bits = ft.encode(z)
+		}
+	}
+
+	if x.Signbit() {
+		bits.SetBit(bits, int(ft.width-1), 1)
+	}
+	return bits, flags
+}
+
+// inf returns the binary representation of the positive infinity.
+func (ft format) inf() *big.Int {
+	bits := big.NewInt(int64(2*ft.emax + 1))
+	return bits.Lsh(bits, ft.prec-1)
+}
+
+// encode returns the binary representation of z.
+// z must be non-negative, finite, and representable in ft.
+func (ft format) encode(z *big.Float) *big.Int {
+	if z.Sign() == 0 {
+		return new(big.Int)
+	}
+
+	emin := 1 - ft.emax
+	e := z.MantExp(nil) - 1
+	if e < emin {
+		// subnormal numbers are the multiples of the smallest subnormal number.
+		i, _ := new(big.Float).SetMantExp(z, int(ft.prec)-1-emin).Int(nil)
+		return i
+	}
+
+	i, _ := new(big.Float).SetMantExp(z, int(ft.prec)-1-e).Int(nil)
+	i.SetBit(i, int(ft.prec-1), 0)
+	biased := big.NewInt(int64(e + ft.emax))
+	return i.Or(i, biased.Lsh(biased, ft.prec-1))
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"math/big"
+	"math/rand"
+)
+
+// generator generates random operands biased toward the edge cases,
+// in the same way as testfloat_gen does.
+type generator struct {
+	r *rand.Rand
+
+	// the options of roundToInt and the conversions to integers.
+	mode  roundingMode
+	exact bool
+}
+
+// the exponents near the boundaries.
+var specialExps128 = []int{
+	0x0001, 0x0002, 0x0003, 0x0070, 0x0071, 0x0072,
+	0x3ffe, 0x3fff, 0x4000, 0x4001, 0x406f, 0x4070,
+	0x7f8e, 0x7ffc, 0x7ffd, 0x7ffe,
+}
+
+var specialExps64 = []int{
+	0x001, 0x002, 0x003, 0x035, 0x036, 0x037,
+	0x3fe, 0x3ff, 0x400, 0x401, 0x433, 0x434,
+	0x7fc, 0x7fd, 0x7fe,
+}
+
+// exp returns a random biased exponent in [0, max].
+// The zero exponent means zeros or subnormal numbers, and max means infinities or NaNs.
+func (g *generator) exp(max int, specials []int) int {
+	switch g.r.Intn(8) {
+	case 0:
+		return 0
+	case 1:
+		return max
+	case 2:
+		return specials[g.r.Intn(len(specials))]
+	case 3:
+		// around one
+		bias := max / 2
+		return bias + g.r.Intn(241) - 120
+	default:
+		return 1 + g.r.Intn(max-1)
+	}
+}
+
+// significand returns random w bits of the significand.
+func (g *generator) significand(w int) (h, l uint64) {
+	set := func(i int) {
+		if i < 64 {
+			l |= 1 << i
+		} else {
+			h |= 1 << (i - 64)
+		}
+	}
+
+	switch g.r.Intn(8) {
+	case 0:
+		// zero
+	case 1:
+		// all ones
+		for i := 0; i < w; i++ {
+			set(i)
+		}
+	case 2:
+		// a single bit
+		set(g.r.Intn(w))
+	case 3:
+		// all ones except a single bit
+		k := g.r.Intn(w)
+		for i := 0; i < w; i++ {
+			if i != k {
+				set(i)
+			}
+		}
+	case 4:
+		// the upper bits are ones
+		for i := g.r.Intn(w); i < w; i++ {
+			set(i)
+		}
+	case 5:
+		// the lower bits are ones
+		for i := g.r.Intn(w); i >= 0; i-- {
+			set(i)
+		}
+	case 6:
+		// runs of ones and zeros
+		one := g.r.Intn(2) == 0
+		for i := w - 1; i >= 0; {
+			for n := 1 + g.r.Intn(w/4); n > 0 && i >= 0; n, i = n-1, i-1 {
+				if one {
+					set(i)
+				}
+			}
+			one = !one
+		}
+	default:
+		h, l = g.r.Uint64(), g.r.Uint64()
+	}
+
+	if w <= 64 {
+		return 0, l & (1<<w - 1)
+	}
+	return h & (1<<(w-64) - 1), l
+}
+
+// f128 returns a random f128.
+func (g *generator) f128() f128 {
+	return g.f128WithExp(g.exp(0x7fff, specialExps128))
+}
+
+func (g *generator) f128WithExp(exp int) f128 {
+	h, l := g.significand(prec128 - 1)
+	sign := g.r.Uint64() & (1 << 63)
+	return f128{sign | uint64(exp)<<48 | h, l}
+}
+
+// f128Pair returns a pair of random f128.
+// Their exponents are often close to each other.
+func (g *generator) f128Pair() (f128, f128) {
+	a := g.f128()
+	if !a.isFinite() {
+		return a, g.f128()
+	}
+
+	switch g.r.Intn(8) {
+	case 0:
+		return a, a
+	case 1:
+		return a, a.neg()
+	case 2, 3, 4:
+		exp := int(a.exp())
+		if g.r.Intn(2) == 0 {
+			exp += g.r.Intn(5) - 2
+		} else {
+			exp += g.r.Intn(2*prec128+1) - prec128
+		}
+		exp = max(0, min(exp, 0x7ffe))
+		return a, g.f128WithExp(exp)
+	}
+	return a, g.f128()
+}
+
+// f128Around returns a random f128 whose biased exponent is in [center-spread, center+spread].
+// It sometimes returns the edge cases of the whole range.
+func (g *generator) f128Around(center, spread int) f128 {
+	if g.r.Intn(8) == 0 {
+		return g.f128()
+	}
+	exp := center + g.r.Intn(2*spread+1) - spread
+	return g.f128WithExp(exp)
+}
+
+// f64 returns a random f64.
+func (g *generator) f64() f64 {
+	exp := g.exp(0x7ff, specialExps64)
+	_, l := g.significand(prec64 - 1)
+	sign := g.r.Uint64() & (1 << 63)
+	return f64(sign | uint64(exp)<<52 | l)
+}
+
+// u64 returns a random 64-bit integer.
+// Its magnitude is distributed uniformly in the number of bits.
+func (g *generator) u64() uint64 {
+	_, l := g.significand(64)
+	return l >> g.r.Intn(64)
+}
+
+// nearTie returns an operand that makes the result of the operation close to a midpoint,
+// i.e. the value just between two adjacent f128 numbers.
+// f computes the operand from the midpoint, and the result is rounded to f128.
+// If the operand is not finite, nearTie returns a random f128.
+func (g *generator) nearTie(f func(m *big.Float) *big.Float) f128 {
+	x := g.f128WithExp(1 + g.r.Intn(0x7ffe))
+
+	// m = x + ulp(x)/2
+	m := x.big()
+	half := new(big.Float).SetMantExp(big.NewFloat(1), m.MantExp(nil)-prec128-1)
+	if m.Signbit() {
+		half.Neg(half)
+	}
+	m.SetPrec(prec128+1).Add(m, half)
+
+	operand, _ := round128(f(m))
+	if !operand.isFinite() || operand.isZero() {
+		return g.f128()
+	}
+	return operand
+}
@@ -0,0 +1,279 @@
+// Command testfloat_gen generates test vectors in the format of testfloat_gen of Berkeley TestFloat,
+// using math/big as the oracle.
+// It doesn't need SoftFloat and TestFloat, so the tests can run completely offline.
+//
+// Usage:
+//
+//	go run ./internal/cmd/testfloat_gen [-seed N] [-n N | -forever] [-r<mode>] [-exact | -notexact] function |
+//		go run ./internal/cmd/float_test function [-r<mode>] [-exact | -notexact]
+//
+// The supported functions are f128_add, f128_mul, f128_div, f128_sqrt, f128_mulAdd, f128_rem, f128_roundToInt,
+// f128_eq, f128_lt, f128_le, f128_to_f64, f64_to_f128, i64_to_f128, ui64_to_f128,
+// f128_to_i64_r_minMag, and f128_to_ui64_r_minMag.
+// The results are rounded to nearest even, and the tininess is detected after rounding.
+//
+// Like testfloat_gen, the rounding mode of f128_roundToInt is selected by
+// -rnear_even (default), -rnear_maxMag, -rminMag, -rmin, -rmax, or -rodd,
+// and -exact makes f128_roundToInt and the conversions to integers raise the inexact exception.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"math/rand"
+	"os"
+	"time"
+)
+
+func main() {
+	seed := flag.Int64("seed", time.Now().UnixNano(), "the seed of the random number generator")
+	n := flag.Int64("n", 100000, "the number of the test vectors")
+	forever := flag.Bool("forever", false, "generate the test vectors forever")
+	mode := roundNearEven
+	for _, opt := range []struct {
+		name string
+		mode roundingMode
+	}{
+		{"rnear_even", roundNearEven},
+		{"rnear_maxMag", roundNearMaxMag},
+		{"rminMag", roundMinMag},
+		{"rmin", roundMin},
+		{"rmax", roundMax},
+		{"rodd", roundOdd},
+	} {
+		opt := opt // go.mod is older than Go 1.22, so the loop variable is shared.
+		flag.BoolFunc(opt.name, "the rounding mode of roundToInt", func(string) error {
+			mode = opt.mode
+			return nil
+		})
+	}
+	exact := false
+	flag.BoolFunc("exact", "roundToInt and the conversions to integers raise the inexact exception", func(string) error {
+		exact = true
+		return nil
+	})
+	flag.BoolFunc("notexact", "roundToInt and the conversions to integers don't raise the inexact exception (default)", func(string) error {
+		exact = false
+		return nil
+	})
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] function\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	gen, ok := functions[flag.Arg(0)]
+	if !ok {
+		log.Fatalf("unknown function: %s", flag.Arg(0))
+	}
+
+	g := &generator{
+		r:     rand.New(rand.NewSource(*seed)),
+		mode:  mode,
+		exact: exact,
+	}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	for i := int64(0); *forever || i < *n; i++ {
+		if _, err := fmt.Fprintln(w, gen(g)); err != nil {
+			// the reader may exit on the first failure.
+			return
+		}
+	}
+}
+
+var functions = map[string]func(g *generator) string{
+	"f128_add":        f128Add,
+	"f128_mul":        f128Mul,
+	"f128_div":        f128Div,
+	"f128_sqrt":       f128Sqrt,
+	"f128_mulAdd":     f128MulAdd,
+	"f128_rem":        f128Rem,
+	"f128_roundToInt": f128RoundToInt,
+	"f128_eq":         f128Eq,
+	"f128_lt":         f128Lt,
+	"f128_le":         f128Le,
+	"f128_to_f64":     f128ToF64,
+	"f64_to_f128":     f64ToF128,
+
+	"i64_to_f128":           i64ToF128,
+	"ui64_to_f128":          ui64ToF128,
+	"f128_to_i64_r_minMag":  f128ToI64,
+	"f128_to_ui64_r_minMag": f128ToUI64,
+}
+
+func f128Add(g *generator) string {
+	a, b := g.f128Pair()
+	c, flags := add(a, b)
+	return fmt.Sprintf("%s %s %s %02X", a, b, c, flags)
+}
+
+func f128Mul(g *generator) string {
+	a := g.f128()
+	var b f128
+	if g.r.Intn(4) == 0 && a.isFinite() && !a.isZero() {
+		// a × b is close to a midpoint.
+		b = g.nearTie(func(m *big.Float) *big.Float {
+			return new(big.Float).SetPrec(2*prec128).Quo(m, a.big())
+		})
+	} else {
+		b = g.f128()
+	}
+	c, flags := mul(a, b)
+	return fmt.Sprintf("%s %s %s %02X", a, b, c, flags)
+}
+
+func f128Div(g *generator) string {
+	a, b := g.f128(), g.f128()
+	if g.r.Intn(4) == 0 && b.isFinite() {
+		// a / b is close to a midpoint.
+		a = g.nearTie(func(m *big.Float) *big.Float {
+			return new(big.Float).SetPrec(2*prec128).Mul(m, b.big())
+		})
+	}
+	c, flags := quo(a, b)
+	return fmt.Sprintf("%s %s %s %02X", a, b, c, flags)
+}
+
+func f128Sqrt(g *generator) string {
+	a := g.f128()
+	if g.r.Intn(4) == 0 {
+		// sqrt(a) is close to a midpoint.
+		a = g.nearTie(func(m *big.Float) *big.Float {
+			return new(big.Float).SetPrec(2*prec128).Mul(m, m)
+		})
+	}
+	b, flags := sqrt(a)
+	return fmt.Sprintf("%s %s %02X", a, b, flags)
+}
+
+func f128MulAdd(g *generator) string {
+	a, b, c := g.f128(), g.f128(), g.f128()
+	if a.isFinite() && b.isFinite() {
+		switch g.r.Intn(4) {
+		case 0:
+			// massive cancellation
+			p := new(big.Float).SetPrec(2*prec128).Mul(a.big(), b.big())
+			c, _ = round128(p.Neg(p))
+		case 1:
+			// a × b + c is close to a midpoint.
+			c = g.nearTie(func(m *big.Float) *big.Float {
+				p := new(big.Float).SetPrec(2*prec128).Mul(a.big(), b.big())
+				return new(big.Float).SetPrec(4*prec128).Sub(m, p)
+			})
+		}
+	}
+	d, flags := fma(a, b, c)
+	return fmt.Sprintf("%s %s %s %s %02X", a, b, c, d, flags)
+}
+
+func f128Rem(g *generator) string {
+	a, b := g.f128Pair()
+	if g.r.Intn(2) == 0 && a.exp() < b.exp() {
+		// the remainder is trivial if |a| < |b|/2.
+		a, b = b, a
+	}
+	if g.r.Intn(4) == 0 && b.isFinite() && !b.isZero() {
+		// a is close to the midpoint of two multiples of b.
+		k := new(big.Float).SetInt64(2*g.r.Int63n(1<<20) + 1)
+		m := new(big.Float).SetPrec(2*prec128).Mul(k, b.big())
+		m.SetMantExp(m, -1)
+		a, _ = round128(m)
+		if g.r.Intn(2) == 0 {
+			a = f128{a.h, a.l ^ 1}
+		}
+	}
+	c, flags := rem(a, b)
+	return fmt.Sprintf("%s %s %s %02X", a, b, c, flags)
+}
+
+func f128RoundToInt(g *generator) string {
+	// the numbers which have the fractional parts are in [2^-2, 2^112).
+	a := g.f128Around(bias128+55, 57)
+	if g.r.Intn(4) == 0 {
+		// a is close to the midpoint of two integers.
+		k := new(big.Float).SetPrec(prec128).SetUint64(g.r.Uint64() >> g.r.Intn(64))
+		k.SetMantExp(k, g.r.Intn(prec128-64))
+		a, _ = round128(k.Add(k, big.NewFloat(0.5)))
+		if g.r.Intn(2) == 0 {
+			a = a.neg()
+		}
+		if g.r.Intn(2) == 0 {
+			a = f128{a.h, a.l ^ 1}
+		}
+	}
+	b, flags := roundToInt(a, g.mode, g.exact)
+	return fmt.Sprintf("%s %s %02X", a, b, flags)
+}
+
+func f128Eq(g *generator) string {
+	a, b := g.f128Pair()
+	c, flags := eq(a, b)
+	return fmt.Sprintf("%s %s %s %02X", a, b, boolString(c), flags)
+}
+
+func f128Lt(g *generator) string {
+	a, b := g.f128Pair()
+	c, flags := lt(a, b)
+	return fmt.Sprintf("%s %s %s %02X", a, b, boolString(c), flags)
+}
+
+func f128Le(g *generator) string {
+	a, b := g.f128Pair()
+	c, flags := le(a, b)
+	return fmt.Sprintf("%s %s %s %02X", a, b, boolString(c), flags)
+}
+
+func f128ToF64(g *generator) string {
+	a := g.f128Around(bias128, bias64+prec64)
+	b, flags := toF64(a)
+	return fmt.Sprintf("%s %s %02X", a, b, flags)
+}
+
+func f64ToF128(g *generator) string {
+	a := g.f64()
+	b, flags := fromF64(a)
+	return fmt.Sprintf("%s %s %02X", a, b, flags)
+}
+
+func i64ToF128(g *generator) string {
+	a := g.u64()
+	if g.r.Intn(2) == 0 {
+		a = -a
+	}
+	b, flags := fromI64(int64(a))
+	return fmt.Sprintf("%016X %s %02X", a, b, flags)
+}
+
+func ui64ToF128(g *generator) string {
+	a := g.u64()
+	b, flags := fromUI64(a)
+	return fmt.Sprintf("%016X %s %02X", a, b, flags)
+}
+
+func f128ToI64(g *generator) string {
+	// the numbers which have the fractional parts or overflow are in [2^-8, 2^72).
+	a := g.f128Around(bias128+32, 40)
+	b, flags := toI64(a, g.exact)
+	return fmt.Sprintf("%s %016X %02X", a, uint64(b), flags)
+}
+
+func f128ToUI64(g *generator) string {
+	a := g.f128Around(bias128+32, 40)
+	b, flags := toUI64(a, g.exact)
+	return fmt.Sprintf("%s %016X %02X", a, b, flags)
+}
+
+func boolString(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"math"
+	"math/big"
+)
+
+// The oracles of the operations.
+// They compute the exact results with math/big, and round them with round128 or round64.
+// The special cases follow the behavior of SoftFloat.
+
+// propagateNaN returns the quiet NaN for the operation on a and b.
+// It raises the invalid exception if either of them is a signaling NaN.
+func propagateNaN(a, b f128) (f128, uint8) {
+	var flags uint8
+	if a.isSignalingNaN() || b.isSignalingNaN() {
+		flags = flagInvalid
+	}
+	if !a.isNaN() {
+		a = b
+	}
+	return a.quiet(), flags
+}
+
+// exactPrec returns the precision that is enough to hold x + y exactly.
+func exactPrec(x, y *big.Float) uint {
+	prec := x.Prec() + y.Prec() + 2
+	if x.Sign() == 0 || y.Sign() == 0 {
+		return prec
+	}
+	d := x.MantExp(nil) - y.MantExp(nil)
+	if d < 0 {
+		d = -d
+	}
+	return prec + uint(d)
+}
+
+// extend makes z which is truncated toward zero more precise by one bit,
+// and sets the last bit if the truncation is inexact.
+// The result is rounded correctly as far as the precision of z is enough.
+func extend(z *big.Float, exact bool) *big.Float {
+	prec := z.Prec() + 1
+	z.SetPrec(prec)
+	if exact {
+		return z
+	}
+	sticky := new(big.Float).SetMantExp(big.NewFloat(1), z.MantExp(nil)-int(prec))
+	if z.Signbit() {
+		sticky.Neg(sticky)
+	}
+	return z.Add(z, sticky)
+}
+
+func add(a, b f128) (f128, uint8) {
+	switch {
+	case a.isNaN() || b.isNaN():
+		return propagateNaN(a, b)
+	case a.isInf() && b.isInf() && a.signbit() != b.signbit():
+		return defaultNaN128, flagInvalid
+	case a.isInf():
+		return a, 0
+	case b.isInf():
+		return b, 0
+	case a.isZero() && b.isZero():
+		if a.signbit() && b.signbit() {
+			return a, 0
+		}
+		return f128{}, 0
+	}
+
+	x, y := a.big(), b.big()
+	return round128(new(big.Float).SetPrec(exactPrec(x, y)).Add(x, y))
+}
+
+func mul(a, b f128) (f128, uint8) {
+	switch {
+	case a.isNaN() || b.isNaN():
+		return propagateNaN(a, b)
+	case a.isInf() && b.isZero(), a.isZero() && b.isInf():
+		return defaultNaN128, flagInvalid
+	}
+
+	x, y := a.big(), b.big()
+	return round128(new(big.Float).SetPrec(2*prec128).Mul(x, y))
+}
+
+func quo(a, b f128) (f128, uint8) {
+	switch {
+	case a.isNaN() || b.isNaN():
+		return propagateNaN(a, b)
+	case a.isInf() && b.isInf(), a.isZero() && b.isZero():
+		return defaultNaN128, flagInvalid
+	case a.isFinite() && b.isZero():
+		inf := f128{h: 0x7fff_0000_0000_0000}
+		if a.signbit() != b.signbit() {
+			inf = inf.neg()
+		}
+		return inf, flagInfinite
+	}
+
+	x, y := a.big(), b.big()
+	if a.isInf() || b.isInf() || a.isZero() {
+		return round128(new(big.Float).Quo(x, y))
+	}
+
+	// compute enough bits of the quotient, and check whether it is exact.
+	z := new(big.Float).SetPrec(4*prec128).SetMode(big.ToZero).Quo(x, y)
+	p := new(big.Float).SetPrec(5*prec128).Mul(z, y)
+	return round128(extend(z, p.Cmp(x) == 0))
+}
+
+func sqrt(a f128) (f128, uint8) {
+	switch {
+	case a.isNaN():
+		return propagateNaN(a, a)
+	case a.isZero():
+		return a, 0
+	case a.signbit():
+		return defaultNaN128, flagInvalid
+	case a.isInf():
+		return a, 0
+	}
+
+	x := a.big()
+	z := new(big.Float).SetPrec(4 * prec128).SetMode(big.ToZero).Sqrt(x)
+	p := new(big.Float).SetPrec(8*prec128).Mul(z, z)
+	return round128(extend(z, p.Cmp(x) == 0))
+}
+
+func fma(a, b, c f128) (f128, uint8) {
+	switch {
+	case (a.isInf() && b.isZero()) || (a.isZero() && b.isInf()):
+		// SoftFloat raises the invalid exception even if c is a quiet NaN.
+		if c.isNaN() {
+			nan, _ := propagateNaN(c, c)
+			return nan, flagInvalid
+		}
+		return defaultNaN128, flagInvalid
+	case a.isNaN() || b.isNaN():
+		nan, flags := propagateNaN(a, b)
+		if c.isSignalingNaN() {
+			flags |= flagInvalid
+		}
+		return nan, flags
+	case c.isNaN():
+		return propagateNaN(c, c)
+	}
+
+	x, y, z := a.big(), b.big(), c.big()
+	if a.isInf() || b.isInf() {
+		p := new(big.Float).Mul(x, y)
+		if c.isInf() && p.Signbit() != c.signbit() {
+			return defaultNaN128, flagInvalid
+		}
+		return round128(p)
+	}
+	if c.isInf() {
+		return c, 0
+	}
+
+	p := new(big.Float).SetPrec(2*prec128).Mul(x, y)
+	if p.Sign() == 0 && z.Sign() == 0 {
+		if p.Signbit() && z.Signbit() {
+			return c, 0
+		}
+		return f128{}, 0
+	}
+	return round128(new(big.Float).SetPrec(exactPrec(p, z)).Add(p, z))
+}
+
+// rem is the remainder of IEEE 754, which is always exact.
+func rem(a, b f128) (f128, uint8) {
+	switch {
+	case a.isNaN() || b.isNaN():
+		return propagateNaN(a, b)
+	case a.isInf() || b.isZero():
+		return defaultNaN128, flagInvalid
+	case b.isInf() || a.isZero():
+		return a, 0
+	}
+
+	// |a| = ma × 2^e and |b| = mb × 2^e for the integers ma and mb.
+	ma, ea := a.mantExp()
+	mb, eb := b.mantExp()
+	e := min(ea, eb)
+	ma.Lsh(ma, uint(ea-e))
+	mb.Lsh(mb, uint(eb-e))
+
+	// round the quotient to nearest even.
+	q, r := new(big.Int).QuoRem(ma, mb, new(big.Int))
+	c := new(big.Int).Lsh(r, 1).Cmp(mb)
+	if c > 0 || c == 0 && q.Bit(0) != 0 {
+		r.Sub(r, mb)
+	}
+
+	z := new(big.Float).SetInt(r)
+	z.SetMantExp(z, e)
+	if a.signbit() {
+		z.Neg(z)
+	}
+	return round128(z)
+}
+
+// roundingMode is the rounding mode of roundToInt, which is selected by the options of testfloat_gen.
+type roundingMode int
+
+const (
+	roundNearEven   roundingMode = iota // -rnear_even
+	roundNearMaxMag                     // -rnear_maxMag
+	roundMinMag                         // -rminMag
+	roundMin                            // -rmin
+	roundMax                            // -rmax
+	roundOdd                            // -rodd
+)
+
+// roundToInt rounds a to an integer in the rounding mode.
+// It raises the inexact exception only if exact is true.
+func roundToInt(a f128, mode roundingMode, exact bool) (f128, uint8) {
+	switch {
+	case a.isNaN():
+		return propagateNaN(a, a)
+	case a.isInf() || a.isZero():
+		return a, 0
+	}
+
+	x := a.big()
+	i, acc := x.Int(nil) // truncated toward zero
+	if acc == big.Exact {
+		return a, 0
+	}
+
+	// compare the discarded fraction with the half.
+	frac := new(big.Float).Sub(x, new(big.Float).SetInt(i))
+	c := frac.Abs(frac).Cmp(big.NewFloat(0.5))
+	odd := i.Bit(0) != 0
+
+	var up bool // whether the magnitude is rounded up
+	switch mode {
+	case roundNearEven:
+		up = c > 0 || c == 0 && odd
+	case roundNearMaxMag:
+		up = c >= 0
+	case roundMin:
+		up = a.signbit()
+	case roundMax:
+		up = !a.signbit()
+	case roundOdd:
+		up = !odd
+	}
+	if up {
+		if a.signbit() {
+			i.Sub(i, big.NewInt(1))
+		} else {
+			i.Add(i, big.NewInt(1))
+		}
+	}
+
+	z := new(big.Float).SetInt(i)
+	if a.signbit() && i.Sign() == 0 {
+		z.Neg(z)
+	}
+	r, _ := round128(z)
+	if exact {
+		return r, flagInexact
+	}
+	return r, 0
+}
+
+// eq is the quiet comparison.
+func eq(a, b f128) (bool, uint8) {
+	if a.isNaN() || b.isNaN() {
+		var flags uint8
+		if a.isSignalingNaN() || b.isSignalingNaN() {
+			flags = flagInvalid
+		}
+		return false, flags
+	}
+	return a.big().Cmp(b.big()) == 0, 0
+}
+
+// lt is the signaling comparison.
+func lt(a, b f128) (bool, uint8) {
+	if a.isNaN() || b.isNaN() {
+		return false, flagInvalid
+	}
+	return a.big().Cmp(b.big()) < 0, 0
+}
+
+// le is the signaling comparison.
+func le(a, b f128) (bool, uint8) {
+	if a.isNaN() || b.isNaN() {
+		return false, flagInvalid
+	}
+	return a.big().Cmp(b.big()) <= 0, 0
+}
+
+// fromI64 converts a to f128, which is always exact.
+func fromI64(a int64) (f128, uint8) {
+	return round128(new(big.Float).SetInt64(a))
+}
+
+// fromUI64 converts a to f128, which is always exact.
+func fromUI64(a uint64) (f128, uint8) {
+	return round128(new(big.Float).SetUint64(a))
+}
+
+// toI64 truncates a toward zero.
+// The result of the invalid conversion is math.MinInt64, the same as SoftFloat for x86.
+// It raises the inexact exception only if exact is true.
+func toI64(a f128, exact bool) (int64, uint8) {
+	if !a.isFinite() {
+		return math.MinInt64, flagInvalid
+	}
+	i, acc := a.big().Int(nil)
+	if !i.IsInt64() {
+		return math.MinInt64, flagInvalid
+	}
+	if exact && acc != big.Exact {
+		return i.Int64(), flagInexact
+	}
+	return i.Int64(), 0
+}
+
+// toUI64 truncates a toward zero.
+// The result of the invalid conversion is math.MaxUint64, the same as SoftFloat for x86.
+// It raises the inexact exception only if exact is true.
+func toUI64(a f128, exact bool) (uint64, uint8) {
+	if !a.isFinite() {
+		return math.MaxUint64, flagInvalid
+	}
+	i, acc := a.big().Int(nil)
+	if !i.IsUint64() {
+		return math.MaxUint64, flagInvalid
+	}
+	if exact && acc != big.Exact {
+		return i.Uint64(), flagInexact
+	}
+	return i.Uint64(), 0
+}
+
+func toF64(a f128) (f64, uint8) {
+	if a.isNaN() {
+		var flags uint8
+		if a.isSignalingNaN() {
+			flags = flagInvalid
+		}
+		// keep the sign and the upper bits of the payload.
+		bits := a.h&(1<<63) | 0x7ff8_0000_0000_0000 | (a.h&(1<<48-1))<<4 | a.l>>60
+		return f64(bits), flags
+	}
+	return round64(a.big())
+}
+
+func fromF64(a f64) (f128, uint8) {
+	if a.isNaN() {
+		var flags uint8
+		if a.isSignalingNaN() {
+			flags = flagInvalid
+		}
+		// keep the sign and the payload.
+		f := f128{
+			h: uint64(a)&(1<<63) | 0x7fff_8000_0000_0000 | (uint64(a)&(1<<52-1))>>4,
+			l: uint64(a) << 60,
+		}
+		return f, flags
+	}
+	return round128(a.big())
+}
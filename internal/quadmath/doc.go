@@ -0,0 +1,11 @@
+// Package quadmath is a differential test of float128 against __float128 of GCC and libquadmath.
+// They are an independent implementation of the same format, so they work as a second oracle besides TestFloat.
+//
+// The test needs cgo and libquadmath, so it is opt-in with the quadmath build tag:
+//
+//	go test -tags quadmath ./internal/quadmath
+//
+// sqrtq of libquadmath is not correctly rounded, so the square roots are allowed to differ by 1 ulp.
+//
+// The number of the random operands for each operation can be changed with the -quadmath.n flag.
+package quadmath
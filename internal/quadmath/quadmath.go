@@ -0,0 +1,242 @@
+//go:build quadmath && cgo
+
+package quadmath
+
+/*
+#cgo LDFLAGS: -lquadmath -lm
+
+#include <fenv.h>
+#include <stdint.h>
+#include <string.h>
+
+// libquadmath doesn't always install quadmath.h, so declare the functions here.
+extern __float128 sqrtq(__float128);
+extern __float128 fmaq(__float128, __float128, __float128);
+
+typedef struct {
+	uint64_t h, l;
+} bits128;
+
+static __float128 fromBits(bits128 b) {
+	// __float128 is little endian on the supported platforms.
+	uint64_t u[2] = {b.l, b.h};
+	__float128 f;
+	memcpy(&f, u, sizeof(f));
+	return f;
+}
+
+static bits128 toBits(__float128 f) {
+	uint64_t u[2];
+	memcpy(u, &f, sizeof(f));
+	bits128 b = {u[1], u[0]};
+	return b;
+}
+
+// the operands are volatile so that the compiler doesn't reorder the operations across fetestexcept.
+
+static bits128 qadd(bits128 a, bits128 b, int *flags) {
+	volatile __float128 x = fromBits(a), y = fromBits(b);
+	feclearexcept(FE_ALL_EXCEPT);
+	volatile __float128 z = x + y;
+	*flags = fetestexcept(FE_ALL_EXCEPT);
+	return toBits(z);
+}
+
+static bits128 qsub(bits128 a, bits128 b, int *flags) {
+	volatile __float128 x = fromBits(a), y = fromBits(b);
+	feclearexcept(FE_ALL_EXCEPT);
+	volatile __float128 z = x - y;
+	*flags = fetestexcept(FE_ALL_EXCEPT);
+	return toBits(z);
+}
+
+static bits128 qmul(bits128 a, bits128 b, int *flags) {
+	volatile __float128 x = fromBits(a), y = fromBits(b);
+	feclearexcept(FE_ALL_EXCEPT);
+	volatile __float128 z = x * y;
+	*flags = fetestexcept(FE_ALL_EXCEPT);
+	return toBits(z);
+}
+
+static bits128 qquo(bits128 a, bits128 b, int *flags) {
+	volatile __float128 x = fromBits(a), y = fromBits(b);
+	feclearexcept(FE_ALL_EXCEPT);
+	volatile __float128 z = x / y;
+	*flags = fetestexcept(FE_ALL_EXCEPT);
+	return toBits(z);
+}
+
+static bits128 qfma(bits128 a, bits128 b, bits128 c, int *flags) {
+	volatile __float128 x = fromBits(a), y = fromBits(b), z = fromBits(c);
+	feclearexcept(FE_ALL_EXCEPT);
+	volatile __float128 w = fmaq(x, y, z);
+	*flags = fetestexcept(FE_ALL_EXCEPT);
+	return toBits(w);
+}
+
+static bits128 qsqrt(bits128 a, int *flags) {
+	volatile __float128 x = fromBits(a);
+	feclearexcept(FE_ALL_EXCEPT);
+	volatile __float128 z = sqrtq(x);
+	*flags = fetestexcept(FE_ALL_EXCEPT);
+	return toBits(z);
+}
+
+static int qeq(bits128 a, bits128 b, int *flags) {
+	volatile __float128 x = fromBits(a), y = fromBits(b);
+	feclearexcept(FE_ALL_EXCEPT);
+	volatile int z = x == y;
+	*flags = fetestexcept(FE_ALL_EXCEPT);
+	return z;
+}
+
+static int qlt(bits128 a, bits128 b, int *flags) {
+	volatile __float128 x = fromBits(a), y = fromBits(b);
+	feclearexcept(FE_ALL_EXCEPT);
+	volatile int z = x < y;
+	*flags = fetestexcept(FE_ALL_EXCEPT);
+	return z;
+}
+
+static int qle(bits128 a, bits128 b, int *flags) {
+	volatile __float128 x = fromBits(a), y = fromBits(b);
+	feclearexcept(FE_ALL_EXCEPT);
+	volatile int z = x <= y;
+	*flags = fetestexcept(FE_ALL_EXCEPT);
+	return z;
+}
+
+static uint64_t qtoFloat64(bits128 a, int *flags) {
+	volatile __float128 x = fromBits(a);
+	feclearexcept(FE_ALL_EXCEPT);
+	volatile double z = (double)x;
+	*flags = fetestexcept(FE_ALL_EXCEPT);
+	uint64_t u;
+	memcpy(&u, (const void *)&z, sizeof(u));
+	return u;
+}
+
+static bits128 qfromFloat64(uint64_t a, int *flags) {
+	double d;
+	memcpy(&d, &a, sizeof(d));
+	volatile double x = d;
+	feclearexcept(FE_ALL_EXCEPT);
+	volatile __float128 z = (__float128)x;
+	*flags = fetestexcept(FE_ALL_EXCEPT);
+	return toBits(z);
+}
+*/
+import "C"
+
+import (
+	"math"
+
+	"github.com/shogo82148/float128"
+)
+
+func toC(f float128.Float128) C.bits128 {
+	h, l := f.Bits()
+	return C.bits128{h: C.uint64_t(h), l: C.uint64_t(l)}
+}
+
+func fromC(b C.bits128) float128.Float128 {
+	return float128.FromBits(uint64(b.h), uint64(b.l))
+}
+
+// toFlags converts the exceptions of fenv.h to float128.Flags.
+func toFlags(except C.int) float128.Flags {
+	var flags float128.Flags
+	if except&C.FE_INEXACT != 0 {
+		flags |= float128.FlagInexact
+	}
+	if except&C.FE_UNDERFLOW != 0 {
+		flags |= float128.FlagUnderflow
+	}
+	if except&C.FE_OVERFLOW != 0 {
+		flags |= float128.FlagOverflow
+	}
+	if except&C.FE_DIVBYZERO != 0 {
+		flags |= float128.FlagDivByZero
+	}
+	if except&C.FE_INVALID != 0 {
+		flags |= float128.FlagInvalid
+	}
+	return flags
+}
+
+// Add returns a + b computed by GCC, and the raised exception flags.
+func Add(a, b float128.Float128) (float128.Float128, float128.Flags) {
+	var except C.int
+	r := C.qadd(toC(a), toC(b), &except)
+	return fromC(r), toFlags(except)
+}
+
+// Sub returns a - b computed by GCC, and the raised exception flags.
+func Sub(a, b float128.Float128) (float128.Float128, float128.Flags) {
+	var except C.int
+	r := C.qsub(toC(a), toC(b), &except)
+	return fromC(r), toFlags(except)
+}
+
+// Mul returns a × b computed by GCC, and the raised exception flags.
+func Mul(a, b float128.Float128) (float128.Float128, float128.Flags) {
+	var except C.int
+	r := C.qmul(toC(a), toC(b), &except)
+	return fromC(r), toFlags(except)
+}
+
+// Quo returns a / b computed by GCC, and the raised exception flags.
+func Quo(a, b float128.Float128) (float128.Float128, float128.Flags) {
+	var except C.int
+	r := C.qquo(toC(a), toC(b), &except)
+	return fromC(r), toFlags(except)
+}
+
+// FMA returns x × y + z computed by fmaq of libquadmath, and the raised exception flags.
+func FMA(x, y, z float128.Float128) (float128.Float128, float128.Flags) {
+	var except C.int
+	r := C.qfma(toC(x), toC(y), toC(z), &except)
+	return fromC(r), toFlags(except)
+}
+
+// Sqrt returns the square root of x computed by sqrtq of libquadmath, and the raised exception flags.
+func Sqrt(x float128.Float128) (float128.Float128, float128.Flags) {
+	var except C.int
+	r := C.qsqrt(toC(x), &except)
+	return fromC(r), toFlags(except)
+}
+
+// Eq returns a == b computed by GCC, and the raised exception flags.
+func Eq(a, b float128.Float128) (bool, float128.Flags) {
+	var except C.int
+	r := C.qeq(toC(a), toC(b), &except)
+	return r != 0, toFlags(except)
+}
+
+// Lt returns a < b computed by GCC, and the raised exception flags.
+func Lt(a, b float128.Float128) (bool, float128.Flags) {
+	var except C.int
+	r := C.qlt(toC(a), toC(b), &except)
+	return r != 0, toFlags(except)
+}
+
+// Le returns a <= b computed by GCC, and the raised exception flags.
+func Le(a, b float128.Float128) (bool, float128.Flags) {
+	var except C.int
+	r := C.qle(toC(a), toC(b), &except)
+	return r != 0, toFlags(except)
+}
+
+// Float64 returns x converted to float64 by GCC, and the raised exception flags.
+func Float64(x float128.Float128) (float64, float128.Flags) {
+	var except C.int
+	r := C.qtoFloat64(toC(x), &except)
+	return math.Float64frombits(uint64(r)), toFlags(except)
+}
+
+// FromFloat64 returns x converted to __float128 by GCC, and the raised exception flags.
+func FromFloat64(x float64) (float128.Float128, float128.Flags) {
+	var except C.int
+	r := C.qfromFloat64(C.uint64_t(math.Float64bits(x)), &except)
+	return fromC(r), toFlags(except)
+}
@@ -0,0 +1,405 @@
+//go:build quadmath && cgo
+
+package quadmath
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/shogo82148/float128"
+)
+
+var n = flag.Int("quadmath.n", 1000000, "the number of the random operands for each operation")
+
+// maxReports is the maximum number of the reported mismatches for each operation.
+const maxReports = 20
+
+// edges are the edge cases of the operands.
+var edges = []float128.Float128{
+	float128.FromBits(0x0000_0000_0000_0000, 0x0000_0000_0000_0000), // +0
+	float128.FromBits(0x8000_0000_0000_0000, 0x0000_0000_0000_0000), // -0
+	float128.FromBits(0x0000_0000_0000_0000, 0x0000_0000_0000_0001), // the smallest subnormal number
+	float128.FromBits(0x0000_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff), // the largest subnormal number
+	float128.FromBits(0x0001_0000_0000_0000, 0x0000_0000_0000_0000), // the smallest normal number
+	float128.FromBits(0x3ffe_0000_0000_0000, 0x0000_0000_0000_0000), // 0.5
+	float128.FromBits(0x3fff_0000_0000_0000, 0x0000_0000_0000_0000), // 1
+	float128.FromBits(0xbfff_0000_0000_0000, 0x0000_0000_0000_0001), // -(1 + ulp)
+	float128.FromBits(0x3fff_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff), // 2 - ulp
+	float128.FromBits(0x4000_8000_0000_0000, 0x0000_0000_0000_0000), // 3
+	float128.FromBits(0x3f8e_0000_0000_0000, 0x0000_0000_0000_0000), // 2^-113
+	float128.FromBits(0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff), // the largest finite number
+	float128.FromBits(0x7fff_0000_0000_0000, 0x0000_0000_0000_0000), // +Inf
+	float128.FromBits(0xffff_0000_0000_0000, 0x0000_0000_0000_0000), // -Inf
+	float128.FromBits(0x7fff_8000_0000_0000, 0x0000_0000_0000_0000), // quiet NaN
+	float128.FromBits(0x7fff_4000_0000_0000, 0x0000_0000_0000_0001), // signaling NaN
+}
+
+// random returns a random Float128 biased toward the edge cases.
+func random(r *rand.Rand) float128.Float128 {
+	var exp uint64
+	switch r.Intn(8) {
+	case 0:
+		exp = 0
+	case 1:
+		exp = 0x7fff
+	case 2:
+		exp = 0x3fff + uint64(r.Intn(241)) - 120
+	case 3:
+		exp = []uint64{1, 2, 0x70, 0x71, 0x7ffd, 0x7ffe}[r.Intn(6)]
+	default:
+		exp = uint64(r.Intn(0x7fff))
+	}
+
+	var h, l uint64
+	switch r.Intn(6) {
+	case 0:
+		// zero
+	case 1:
+		// all ones
+		h, l = math.MaxUint64, math.MaxUint64
+	case 2:
+		// a single bit
+		if i := r.Intn(112); i < 64 {
+			l = 1 << i
+		} else {
+			h = 1 << (i - 64)
+		}
+	case 3:
+		// the upper bits are ones
+		h, l = math.MaxUint64, math.MaxUint64
+		if i := r.Intn(112); i < 64 {
+			l &^= 1<<i - 1
+		} else {
+			h &^= 1<<(i-64) - 1
+			l = 0
+		}
+	default:
+		h, l = r.Uint64(), r.Uint64()
+	}
+	sign := r.Uint64() & (1 << 63)
+	return float128.FromBits(sign|exp<<48|h&(1<<48-1), l)
+}
+
+// randomPair returns a pair of random Float128, whose exponents are often close to each other.
+func randomPair(r *rand.Rand) (float128.Float128, float128.Float128) {
+	a, b := random(r), random(r)
+	if r.Intn(2) == 0 {
+		ah, _ := a.Bits()
+		bh, bl := b.Bits()
+		exp := int64(ah>>48&0x7fff) + int64(r.Intn(241)) - 120
+		exp = max(0, min(exp, 0x7ffe))
+		b = float128.FromBits(bh&^(0x7fff<<48)|uint64(exp)<<48, bl)
+	}
+	return a, b
+}
+
+// ulpDistance returns the number of the floating point numbers between a and b.
+func ulpDistance(a, b float128.Float128) *big.Int {
+	key := func(f float128.Float128) *big.Int {
+		h, l := f.Bits()
+		k := new(big.Int).SetUint64(h &^ (1 << 63))
+		k.Lsh(k, 64).Or(k, new(big.Int).SetUint64(l))
+		if h>>63 != 0 {
+			k.Neg(k)
+		}
+		return k
+	}
+	d := new(big.Int).Sub(key(a), key(b))
+	return d.Abs(d)
+}
+
+// same reports whether a and b are the same, treating all NaNs as the same.
+func same(a, b float128.Float128) bool {
+	if a.IsNaN() && b.IsNaN() {
+		return true
+	}
+	return a == b
+}
+
+func dump(f float128.Float128) string {
+	h, l := f.Bits()
+	return fmt.Sprintf("%016x_%016x", h, l)
+}
+
+// checker reports the mismatches of an operation.
+type checker struct {
+	t        *testing.T
+	name     string
+	failures int
+}
+
+func (c *checker) check(args string, got float128.Float128, gotFlags float128.Flags, want float128.Float128, wantFlags float128.Flags) {
+	c.t.Helper()
+	if same(got, want) && gotFlags == wantFlags {
+		return
+	}
+	c.failures++
+	if c.failures > maxReports {
+		return
+	}
+	var ulp string
+	if !got.IsNaN() && !want.IsNaN() && !got.IsInf(0) && !want.IsInf(0) {
+		ulp = fmt.Sprintf(" (%s ulp)", ulpDistance(got, want))
+	}
+	c.t.Errorf("%s(%s): got %s, %s, want %s, %s%s", c.name, args, dump(got), gotFlags, dump(want), wantFlags, ulp)
+}
+
+func (c *checker) done() {
+	c.t.Helper()
+	if c.failures > maxReports {
+		c.t.Errorf("%s: %d mismatches in total", c.name, c.failures)
+	}
+}
+
+func testBinary(t *testing.T, name string, f func(a, b float128.Float128) (float128.Float128, float128.Flags), want func(a, b float128.Float128) (float128.Float128, float128.Flags)) {
+	c := &checker{t: t, name: name}
+	check := func(a, b float128.Float128) {
+		got, gotFlags := f(a, b)
+		w, wFlags := want(a, b)
+		c.check(dump(a)+", "+dump(b), got, gotFlags, w, wFlags)
+	}
+	for _, a := range edges {
+		for _, b := range edges {
+			check(a, b)
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < *n; i++ {
+		check(randomPair(r))
+	}
+	c.done()
+}
+
+func TestAdd(t *testing.T) {
+	testBinary(t, "Add", func(a, b float128.Float128) (float128.Float128, float128.Flags) {
+		return a.Add(b), 0
+	}, func(a, b float128.Float128) (float128.Float128, float128.Flags) {
+		got, _ := Add(a, b)
+		return got, 0
+	})
+	testBinary(t, "AddFlags", func(a, b float128.Float128) (float128.Float128, float128.Flags) {
+		return a.AddFlags(b, float128.ToNearestEven)
+	}, Add)
+}
+
+func TestSub(t *testing.T) {
+	testBinary(t, "Sub", func(a, b float128.Float128) (float128.Float128, float128.Flags) {
+		return a.Sub(b), 0
+	}, func(a, b float128.Float128) (float128.Float128, float128.Flags) {
+		got, _ := Sub(a, b)
+		return got, 0
+	})
+	testBinary(t, "SubFlags", func(a, b float128.Float128) (float128.Float128, float128.Flags) {
+		return a.SubFlags(b, float128.ToNearestEven)
+	}, Sub)
+}
+
+func TestMul(t *testing.T) {
+	testBinary(t, "Mul", func(a, b float128.Float128) (float128.Float128, float128.Flags) {
+		return a.Mul(b), 0
+	}, func(a, b float128.Float128) (float128.Float128, float128.Flags) {
+		got, _ := Mul(a, b)
+		return got, 0
+	})
+	testBinary(t, "MulFlags", func(a, b float128.Float128) (float128.Float128, float128.Flags) {
+		return a.MulFlags(b, float128.ToNearestEven)
+	}, Mul)
+}
+
+func TestQuo(t *testing.T) {
+	testBinary(t, "Quo", func(a, b float128.Float128) (float128.Float128, float128.Flags) {
+		return a.Quo(b), 0
+	}, func(a, b float128.Float128) (float128.Float128, float128.Flags) {
+		got, _ := Quo(a, b)
+		return got, 0
+	})
+	testBinary(t, "QuoFlags", func(a, b float128.Float128) (float128.Float128, float128.Flags) {
+		return a.QuoFlags(b, float128.ToNearestEven)
+	}, Quo)
+}
+
+func TestFMA(t *testing.T) {
+	c := &checker{t: t, name: "FMA"}
+	cf := &checker{t: t, name: "FMAFlags"}
+	check := func(x, y, z float128.Float128) {
+		args := dump(x) + ", " + dump(y) + ", " + dump(z)
+		want, wantFlags := FMA(x, y, z)
+		c.check(args, float128.FMA(x, y, z), 0, want, 0)
+		got, gotFlags := float128.FMAFlags(x, y, z, float128.ToNearestEven)
+		cf.check(args, got, gotFlags, want, wantFlags)
+	}
+	for _, x := range edges {
+		for _, y := range edges {
+			for _, z := range edges {
+				check(x, y, z)
+			}
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < *n; i++ {
+		x, y := randomPair(r)
+		z := random(r)
+		if r.Intn(2) == 0 {
+			// make the cancellation likely.
+			z, _ = Mul(x, y)
+			z = z.Neg()
+			if r.Intn(2) == 0 {
+				h, l := z.Bits()
+				z = float128.FromBits(h, l^uint64(r.Intn(4)))
+			}
+		}
+		check(x, y, z)
+	}
+	c.done()
+	cf.done()
+}
+
+func TestSqrt(t *testing.T) {
+	// sqrtq of libquadmath is not correctly rounded, and its exception flags are not reliable.
+	// So only the differences of more than 1 ulp are errors, and the others are just counted.
+	failures, inexact := 0, 0
+	one := big.NewInt(1)
+	check := func(x float128.Float128) {
+		want, _ := Sqrt(x)
+		got := x.Sqrt()
+		got2, _ := x.SqrtFlags(float128.ToNearestEven)
+		if got != got2 && !(got.IsNaN() && got2.IsNaN()) {
+			t.Errorf("Sqrt(%s) = %s, but SqrtFlags returns %s", dump(x), dump(got), dump(got2))
+		}
+		if same(got, want) {
+			return
+		}
+		if !got.IsNaN() && !want.IsNaN() && ulpDistance(got, want).Cmp(one) <= 0 {
+			inexact++
+			return
+		}
+		failures++
+		if failures <= maxReports {
+			t.Errorf("Sqrt(%s): got %s, want %s", dump(x), dump(got), dump(want))
+		}
+	}
+	for _, x := range edges {
+		check(x)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < *n; i++ {
+		check(random(r))
+	}
+	if failures > maxReports {
+		t.Errorf("Sqrt: %d mismatches in total", failures)
+	}
+	if inexact > 0 {
+		t.Logf("Sqrt: %d results differ from sqrtq by 1 ulp", inexact)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func(a, b float128.Float128) (bool, float128.Flags)
+		fast func(a, b float128.Float128) bool
+		want func(a, b float128.Float128) (bool, float128.Flags)
+	}{
+		{"Eq", float128.Float128.EqFlags, float128.Float128.Eq, Eq},
+		{"Lt", float128.Float128.LtFlags, float128.Float128.Lt, Lt},
+		{"Le", float128.Float128.LeFlags, float128.Float128.Le, Le},
+	}
+	for _, tt := range tests {
+		failures := 0
+		check := func(a, b float128.Float128) {
+			want, wantFlags := tt.want(a, b)
+			got, gotFlags := tt.f(a, b)
+			fast := tt.fast(a, b)
+			if got == want && fast == want && gotFlags == wantFlags {
+				return
+			}
+			failures++
+			if failures <= maxReports {
+				t.Errorf("%s(%s, %s): got %t, %t, %s, want %t, %s", tt.name, dump(a), dump(b), fast, got, gotFlags, want, wantFlags)
+			}
+		}
+		for _, a := range edges {
+			for _, b := range edges {
+				check(a, b)
+			}
+		}
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < *n; i++ {
+			a, b := randomPair(r)
+			if r.Intn(8) == 0 {
+				b = a
+			}
+			check(a, b)
+		}
+		if failures > maxReports {
+			t.Errorf("%s: %d mismatches in total", tt.name, failures)
+		}
+	}
+}
+
+func TestFloat64(t *testing.T) {
+	failures := 0
+	check := func(x float128.Float128) {
+		want, wantFlags := Float64(x)
+		fast := x.Float64()
+		got, gotFlags := x.Float64Flags(float128.ToNearestEven)
+		same := func(a, b float64) bool {
+			return math.Float64bits(a) == math.Float64bits(b) || math.IsNaN(a) && math.IsNaN(b)
+		}
+		if same(got, want) && same(fast, want) && gotFlags == wantFlags {
+			return
+		}
+		failures++
+		if failures <= maxReports {
+			t.Errorf("Float64(%s): got %x, %x, %s, want %x, %s", dump(x), fast, got, gotFlags, want, wantFlags)
+		}
+	}
+	for _, x := range edges {
+		check(x)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < *n; i++ {
+		// the exponents around the range of float64.
+		x := random(r)
+		if r.Intn(2) == 0 {
+			h, l := x.Bits()
+			exp := uint64(0x3fff + r.Intn(2*1100+1) - 1100)
+			x = float128.FromBits(h&^(0x7fff<<48)|exp<<48, l)
+		}
+		check(x)
+	}
+	if failures > maxReports {
+		t.Errorf("Float64: %d mismatches in total", failures)
+	}
+}
+
+func TestFromFloat64(t *testing.T) {
+	c := &checker{t: t, name: "FromFloat64"}
+	cf := &checker{t: t, name: "FromFloat64Flags"}
+	check := func(x float64) {
+		args := fmt.Sprintf("%x", x)
+		want, wantFlags := FromFloat64(x)
+		c.check(args, float128.FromFloat64(x), 0, want, 0)
+		got, gotFlags := float128.FromFloat64Flags(x)
+		cf.check(args, got, gotFlags, want, wantFlags)
+	}
+	for _, bits := range []uint64{
+		0x0000_0000_0000_0000, 0x8000_0000_0000_0000, // ±0
+		0x0000_0000_0000_0001, 0x000f_ffff_ffff_ffff, // subnormal numbers
+		0x0010_0000_0000_0000, 0x7fef_ffff_ffff_ffff, // normal numbers
+		0x7ff0_0000_0000_0000, 0xfff0_0000_0000_0000, // ±Inf
+		0x7ff8_0000_0000_0000, 0x7ff0_0000_0000_0001, // NaNs
+	} {
+		check(math.Float64frombits(bits))
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < *n; i++ {
+		check(math.Float64frombits(r.Uint64()))
+	}
+	c.done()
+	cf.done()
+}
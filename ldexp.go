@@ -0,0 +1,100 @@
+package float128
+
+import "math"
+
+// maxScale is large enough to scale any finite non-zero number to overflow or underflow.
+const maxScale = 2 * (bias128 + shift128 + 1)
+
+// Frexp breaks f into a normalized fraction and an integral power of two.
+// It returns frac and exp satisfying f == frac × 2**exp,
+// with the absolute value of frac in the interval [½, 1).
+//
+// Special cases are:
+//
+//	Frexp(±0) = ±0, 0
+//	Frexp(±Inf) = ±Inf, 0
+//	Frexp(NaN) = NaN, 0
+func (f Float128) Frexp() (frac Float128, exp int) {
+	switch {
+	case f.IsNaN():
+		return propagateNaN(f, f), 0
+	case f.IsInf(0) || f.isZero():
+		return f, 0
+	}
+
+	sign, e, fracF := f.split()
+	frac = Float128{sign | (bias128-1)<<(shift128-64) | fracF.H&fracMask128H, fracF.L}
+	return frac, int(e) + 1
+}
+
+// Ldexp is the inverse of [Float128.Frexp].
+// It returns frac × 2**exp, rounded to nearest even if the result is subnormal.
+//
+// Special cases are:
+//
+//	Ldexp(±0, exp) = ±0
+//	Ldexp(±Inf, exp) = ±Inf
+//	Ldexp(NaN, exp) = NaN
+func Ldexp(frac Float128, exp int) Float128 {
+	f, _ := frac.ScaleBFlags(exp, ToNearestEven)
+	return f
+}
+
+// ScaleBFlags returns x × 2**n rounded in the rounding mode, and the raised exception flags,
+// as scaleB of IEEE 754.
+// The result is exact unless it overflows or it is subnormal.
+func (x Float128) ScaleBFlags(n int, mode RoundingMode) (Float128, Flags) {
+	switch {
+	case x.IsNaN():
+		return propagateNaNFlags(x, x)
+	case x.IsInf(0) || x.isZero():
+		return x, 0
+	}
+
+	// clamp n so that the exponent doesn't overflow int32.
+	n = min(max(n, -maxScale), maxScale)
+
+	sign, exp, frac := x.split()
+	f, _, flags := packRound(sign, exp-shift128+int32(n), frac, 0, mode)
+	return f, flags
+}
+
+// Ilogb returns the binary exponent of f as an integer.
+// Subnormal numbers are treated as though they were normalized.
+//
+// Special cases are:
+//
+//	Ilogb(±Inf) = MaxInt32
+//	Ilogb(0) = MinInt32
+//	Ilogb(NaN) = MaxInt32
+func (f Float128) Ilogb() int {
+	switch {
+	case f.IsNaN() || f.IsInf(0):
+		return math.MaxInt32
+	case f.isZero():
+		return math.MinInt32
+	}
+	_, exp, _ := f.split()
+	return int(exp)
+}
+
+// Logb returns the binary exponent of f.
+// Subnormal numbers are treated as though they were normalized.
+//
+// Special cases are:
+//
+//	Logb(±Inf) = +Inf
+//	Logb(0) = -Inf
+//	Logb(NaN) = NaN
+func (f Float128) Logb() Float128 {
+	switch {
+	case f.IsNaN():
+		return propagateNaN(f, f)
+	case f.IsInf(0):
+		return inf
+	case f.isZero():
+		return neginf
+	}
+	_, exp, _ := f.split()
+	return FromInt64(int64(exp))
+}
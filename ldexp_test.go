@@ -0,0 +1,189 @@
+package float128
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestFrexp(t *testing.T) {
+	tests := []struct {
+		input Float128
+		frac  Float128
+		exp   int
+	}{
+		// 1 = 0.5 × 2^1
+		{Float128{0x3fff_0000_0000_0000, 0}, Float128{0x3ffe_0000_0000_0000, 0}, 1},
+		// -3 = -0.75 × 2^2
+		{Float128{0xc000_8000_0000_0000, 0}, Float128{0xbffe_8000_0000_0000, 0}, 2},
+		// the largest finite number
+		{Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, Float128{0x3ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, 16384},
+		// the smallest normal number
+		{Float128{0x0001_0000_0000_0000, 0}, Float128{0x3ffe_0000_0000_0000, 0}, -16381},
+		// the smallest positive subnormal number
+		{Float128{0, 1}, Float128{0x3ffe_0000_0000_0000, 0}, -16493},
+		// the largest subnormal number
+		{Float128{0x8000_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, Float128{0xbffe_ffff_ffff_ffff, 0xffff_ffff_ffff_fffe}, -16382},
+
+		// special cases
+		{Float128{0x8000_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 0}, 0},
+		{Inf(-1), Inf(-1), 0},
+		{NaN(), NaN(), 0},
+	}
+
+	for _, tt := range tests {
+		frac, exp := tt.input.Frexp()
+		if frac != tt.frac || exp != tt.exp {
+			t.Errorf("%s.Frexp() = %s, %d, want %s, %d", dump(tt.input), dump(frac), exp, dump(tt.frac), tt.exp)
+		}
+		if got := Ldexp(frac, exp); got != tt.input {
+			t.Errorf("Ldexp(%s, %d) = %s, want %s", dump(frac), exp, dump(got), dump(tt.input))
+		}
+	}
+}
+
+func TestScaleBFlags(t *testing.T) {
+	tests := []struct {
+		x     Float128
+		n     int
+		mode  RoundingMode
+		want  Float128
+		flags Flags
+	}{
+		// 1 × 2^10 = 1024
+		{Float128{0x3fff_0000_0000_0000, 0}, 10, ToNearestEven, Float128{0x4009_0000_0000_0000, 0}, 0},
+		// across the subnormal boundary without rounding
+		{Float128{0x3fff_0000_0000_0000, 0}, -16494, ToNearestEven, Float128{0, 1}, 0},
+		{Float128{0, 1}, 16494, ToNearestEven, Float128{0x3fff_0000_0000_0000, 0}, 0},
+		{Float128{0x0000_8000_0000_0000, 0}, 1, ToNearestEven, Float128{0x0001_0000_0000_0000, 0}, 0},
+
+		// 1.5 × 2^-16494 rounds to 2 × 2^-16494
+		{Float128{0x3fff_8000_0000_0000, 0}, -16494, ToNearestEven, Float128{0, 2}, FlagInexact | FlagUnderflow},
+		{Float128{0x3fff_8000_0000_0000, 0}, -16494, ToZero, Float128{0, 1}, FlagInexact | FlagUnderflow},
+		// 2.5 × 2^-16494 rounds to 2 × 2^-16494
+		{Float128{0x4000_4000_0000_0000, 0}, -16494, ToNearestEven, Float128{0, 2}, FlagInexact | FlagUnderflow},
+		{Float128{0xc000_4000_0000_0000, 0}, -16494, ToNearestAway, Float128{0x8000_0000_0000_0000, 3}, FlagInexact | FlagUnderflow},
+		// 2^-16495 is the midpoint between 0 and the smallest subnormal number
+		{Float128{0x3fff_0000_0000_0000, 0}, -16495, ToNearestEven, Float128{}, FlagInexact | FlagUnderflow},
+		{Float128{0x3fff_0000_0000_0000, 0}, -16495, ToPositiveInf, Float128{0, 1}, FlagInexact | FlagUnderflow},
+		{Float128{0xbfff_0000_0000_0000, 0}, -20000, ToNearestEven, Float128{0x8000_0000_0000_0000, 0}, FlagInexact | FlagUnderflow},
+		// rounding up to the smallest normal number.
+		// it underflows, because the exact value has 113 bits and it is tiny after rounding with unbounded exponent range.
+		{Float128{0x3ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, -16382, ToNearestEven, Float128{0x0001_0000_0000_0000, 0}, FlagInexact | FlagUnderflow},
+		// (1 - 2^-113) × 2^-16383 rounds up to 2^-16383, which is subnormal.
+		{Float128{0x3ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, -16383, ToNearestEven, Float128{0x0000_8000_0000_0000, 0}, FlagInexact | FlagUnderflow},
+
+		// overflow
+		{Float128{0x3fff_0000_0000_0000, 0}, 16384, ToNearestEven, Inf(1), FlagInexact | FlagOverflow},
+		{Float128{0x3fff_0000_0000_0000, 0}, 16384, ToZero, Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, FlagInexact | FlagOverflow},
+		{Float128{0, 1}, math.MaxInt, ToNearestEven, Inf(1), FlagInexact | FlagOverflow},
+		{Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, math.MinInt, ToNearestEven, Float128{}, FlagInexact | FlagUnderflow},
+
+		// special cases
+		{Float128{0x8000_0000_0000_0000, 0}, 100, ToNearestEven, Float128{0x8000_0000_0000_0000, 0}, 0},
+		{Inf(-1), -100, ToNearestEven, Inf(-1), 0},
+		{Float128{0x7fff_0000_0000_0000, 1}, 1, ToNearestEven, Float128{0x7fff_8000_0000_0000, 1}, FlagInvalid},
+	}
+
+	for _, tt := range tests {
+		got, flags := tt.x.ScaleBFlags(tt.n, tt.mode)
+		if got != tt.want || flags != tt.flags {
+			t.Errorf("%s.ScaleBFlags(%d, %s) = %s, %s, want %s, %s", dump(tt.x), tt.n, tt.mode, dump(got), flags, dump(tt.want), tt.flags)
+		}
+
+		ctx := &Context{Mode: tt.mode}
+		if got := ctx.ScaleB(tt.x, tt.n); got != tt.want || ctx.Flags != tt.flags {
+			t.Errorf("Context{Mode: %s}.ScaleB(%s, %d) = %s, %s, want %s, %s", tt.mode, dump(tt.x), tt.n, dump(got), ctx.Flags, dump(tt.want), tt.flags)
+		}
+
+		if tt.mode == ToNearestEven {
+			if got := Ldexp(tt.x, tt.n); got != tt.want {
+				t.Errorf("Ldexp(%s, %d) = %s, want %s", dump(tt.x), tt.n, dump(got), dump(tt.want))
+			}
+		}
+	}
+}
+
+func TestScaleBFlags_Random(t *testing.T) {
+	modes := []RoundingMode{ToNearestEven, ToNearestAway, ToZero, AwayFromZero, ToNegativeInf, ToPositiveInf}
+	r := newXoshiro256pp()
+	for i := 0; i < 100000; i++ {
+		x, _ := r.Float128Pair()
+		if x.IsNaN() || x.IsInf(0) || x.isZero() {
+			continue
+		}
+		n := int(int64(r.Uint64()) % (2 * maxScale))
+		mode := modes[i%len(modes)]
+
+		exact := new(big.Float).SetMantExp(x.ToBigFloat(), n)
+		want := roundBig(exact, big.RoundingMode(mode))
+		got, flags := x.ScaleBFlags(n, mode)
+		if got.IsNaN() || got.ToBigFloat().Cmp(want) != 0 || got.Signbit() != want.Signbit() {
+			t.Errorf("%s.ScaleBFlags(%d, %s) = %s, want %s", dump(x), n, mode, dump(got), want.Text('p', 0))
+		}
+		if inexact := want.Cmp(exact) != 0; inexact != (flags&FlagInexact != 0) {
+			t.Errorf("%s.ScaleBFlags(%d, %s): got flags %s, want inexact = %t", dump(x), n, mode, flags, inexact)
+		}
+	}
+}
+
+func TestIlogb(t *testing.T) {
+	tests := []struct {
+		input Float128
+		ilogb int
+		logb  Float128
+	}{
+		{Float128{0x3fff_0000_0000_0000, 0}, 0, Float128{}},                                 // 1
+		{Float128{0xc000_8000_0000_0000, 0}, 1, Float128{0x3fff_0000_0000_0000, 0}},         // -3
+		{Float128{0x3ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, -1, FromInt64(-1)},         // 1 - 2^-113
+		{Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, 16383, FromInt64(16383)},   // the largest finite number
+		{Float128{0x0001_0000_0000_0000, 0}, -16382, FromInt64(-16382)},                     // the smallest normal number
+		{Float128{0x8000_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, -16383, FromInt64(-16383)}, // the largest subnormal number
+		{Float128{0, 1}, -16494, FromInt64(-16494)},                                         // the smallest subnormal number
+
+		// special cases
+		{Inf(1), math.MaxInt32, Inf(1)},
+		{Inf(-1), math.MaxInt32, Inf(1)},
+		{Float128{}, math.MinInt32, Inf(-1)},
+		{Float128{0x8000_0000_0000_0000, 0}, math.MinInt32, Inf(-1)},
+		{NaN(), math.MaxInt32, NaN()},
+	}
+
+	for _, tt := range tests {
+		if got := tt.input.Ilogb(); got != tt.ilogb {
+			t.Errorf("%s.Ilogb() = %d, want %d", dump(tt.input), got, tt.ilogb)
+		}
+		if got := tt.input.Logb(); got != tt.logb {
+			t.Errorf("%s.Logb() = %s, want %s", dump(tt.input), dump(got), dump(tt.logb))
+		}
+	}
+}
+
+func TestLdexp_Float64(t *testing.T) {
+	// the results agree with the math package.
+	r := newXoshiro256pp()
+	for i := 0; i < 100000; i++ {
+		a := math.Float64frombits(r.Uint64())
+		n := int(int64(r.Uint64())%2200) - 1100
+		x := FromFloat64(a)
+
+		frac, exp := x.Frexp()
+		wantFrac, wantExp := math.Frexp(a)
+		if math.Float64bits(frac.Float64()) != math.Float64bits(wantFrac) && !math.IsNaN(wantFrac) || exp != wantExp {
+			t.Errorf("Frexp(%x) = %x, %d, want %x, %d", a, frac.Float64(), exp, wantFrac, wantExp)
+		}
+
+		// the result of Ldexp is exact in Float128, so it is rounded only once.
+		got, want := Ldexp(x, n).Float64(), math.Ldexp(a, n)
+		if math.Float64bits(got) != math.Float64bits(want) && !math.IsNaN(want) {
+			t.Errorf("Ldexp(%x, %d) = %x, want %x", a, n, got, want)
+		}
+
+		if got, want := x.Ilogb(), math.Ilogb(a); got != want {
+			t.Errorf("Ilogb(%x) = %d, want %d", a, got, want)
+		}
+		if got, want := x.Logb().Float64(), math.Logb(a); math.Float64bits(got) != math.Float64bits(want) && !math.IsNaN(want) {
+			t.Errorf("Logb(%x) = %x, want %x", a, got, want)
+		}
+	}
+}
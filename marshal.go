@@ -0,0 +1,183 @@
+package float128
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	"github.com/shogo82148/int128"
+)
+
+// MarshalText implements [encoding.TextMarshaler].
+// The result is the shortest decimal representation that round-trips,
+// the same as [Float128.String].
+// NaN and ±Inf are encoded as "NaN", "+Inf" and "-Inf".
+func (f Float128) MarshalText() ([]byte, error) {
+	return AppendFloat(nil, f, 'g', -1), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+// It accepts the syntax of [Parse].
+func (f *Float128) UnmarshalText(text []byte) error {
+	v, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*f = v
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler].
+// Finite numbers are encoded as JSON numbers with the shortest decimal representation that round-trips.
+// JSON numbers cannot represent NaN and ±Inf, so they are encoded as JSON strings "NaN", "+Inf" and "-Inf".
+//
+// Use [JSONString] to encode finite numbers as JSON strings, too.
+func (f Float128) MarshalJSON() ([]byte, error) {
+	if f.IsNaN() || f.IsInf(0) {
+		return JSONString(f).MarshalJSON()
+	}
+	return AppendFloat(nil, f, 'g', -1), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+// It accepts both JSON numbers and JSON strings in the syntax of [Parse].
+// The JSON null value is ignored.
+func (f *Float128) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	} else if len(data) == 0 || !(data[0] == '-' || '0' <= data[0] && data[0] <= '9') {
+		return errors.New("float128: cannot unmarshal " + s + " into Float128")
+	}
+	return f.UnmarshalText([]byte(s))
+}
+
+// JSONString is a Float128 that is encoded as a JSON string.
+// It protects the precision of the value from JSON decoders that parse JSON numbers as float64.
+type JSONString Float128
+
+// MarshalJSON implements [json.Marshaler].
+// f is encoded as a JSON string with the shortest decimal representation that round-trips.
+func (f JSONString) MarshalJSON() ([]byte, error) {
+	buf := make([]byte, 0, 48)
+	buf = append(buf, '"')
+	buf = AppendFloat(buf, Float128(f), 'g', -1)
+	buf = append(buf, '"')
+	return buf, nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+// It accepts both JSON numbers and JSON strings in the syntax of [Parse].
+// The JSON null value is ignored.
+func (f *JSONString) UnmarshalJSON(data []byte) error {
+	return (*Float128)(f).UnmarshalJSON(data)
+}
+
+// PutBytesLE stores f into b[:16] in the little-endian byte order,
+// which is the same layout as __float128 of C on little-endian machines.
+// It panics if len(b) < 16.
+func (f Float128) PutBytesLE(b []byte) {
+	_ = b[15] // early bounds check to guarantee safety of writes below
+	binary.LittleEndian.PutUint64(b[:8], f.l)
+	binary.LittleEndian.PutUint64(b[8:16], f.h)
+}
+
+// PutBytesBE stores f into b[:16] in the big-endian byte order.
+// It panics if len(b) < 16.
+func (f Float128) PutBytesBE(b []byte) {
+	_ = b[15] // early bounds check to guarantee safety of writes below
+	binary.BigEndian.PutUint64(b[:8], f.h)
+	binary.BigEndian.PutUint64(b[8:16], f.l)
+}
+
+// FromBytesLE returns the floating point number stored in b[:16] in the little-endian byte order.
+// It panics if len(b) < 16.
+func FromBytesLE(b []byte) Float128 {
+	_ = b[15] // bounds check hint to compiler
+	return Float128{
+		h: binary.LittleEndian.Uint64(b[8:16]),
+		l: binary.LittleEndian.Uint64(b[:8]),
+	}
+}
+
+// FromBytesBE returns the floating point number stored in b[:16] in the big-endian byte order.
+// It panics if len(b) < 16.
+func FromBytesBE(b []byte) Float128 {
+	_ = b[15] // bounds check hint to compiler
+	return Float128{
+		h: binary.BigEndian.Uint64(b[:8]),
+		l: binary.BigEndian.Uint64(b[8:16]),
+	}
+}
+
+// AppendBinary implements [encoding.BinaryAppender].
+// It appends the IEEE 754 binary128 interchange format of f in the little-endian byte order to b.
+func (f Float128) AppendBinary(b []byte) ([]byte, error) {
+	b = binary.LittleEndian.AppendUint64(b, f.l)
+	b = binary.LittleEndian.AppendUint64(b, f.h)
+	return b, nil
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler].
+// The result is the IEEE 754 binary128 interchange format of f in the little-endian byte order,
+// which is the same layout as __float128 of C on little-endian machines.
+func (f Float128) MarshalBinary() ([]byte, error) {
+	return f.AppendBinary(make([]byte, 0, 16))
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+// It decodes the format of [Float128.MarshalBinary].
+func (f *Float128) UnmarshalBinary(data []byte) error {
+	if len(data) != 16 {
+		return errors.New("float128: invalid length of binary data")
+	}
+	*f = FromBytesLE(data)
+	return nil
+}
+
+// AppendSortKey appends the order-preserving key of f to dst and returns the extended buffer.
+// The key is 16 bytes long, and the lexicographic byte order of the keys matches
+// the totalOrder predicate of IEEE 754, that is:
+//
+//	-NaN < -Inf < negative numbers < -0 < +0 < positive numbers < +Inf < +NaN
+//
+// NaNs are ordered by their payloads, and the keys of distinct bit patterns are distinct.
+// The order of the keys is the same as [Float128.CompareTotal].
+// It is useful for the keys of key-value stores and indexes.
+func (f Float128) AppendSortKey(dst []byte) []byte {
+	key := f.sortKey()
+	dst = binary.BigEndian.AppendUint64(dst, key.H)
+	dst = binary.BigEndian.AppendUint64(dst, key.L)
+	return dst
+}
+
+// FromSortKey returns the floating point number of the key stored in key[:16],
+// which is generated by [Float128.AppendSortKey].
+// It panics if len(key) < 16.
+func FromSortKey(key []byte) Float128 {
+	_ = key[15] // bounds check hint to compiler
+	h := binary.BigEndian.Uint64(key[:8])
+	l := binary.BigEndian.Uint64(key[8:16])
+	if h&signMask128H != 0 {
+		// positive
+		return Float128{h ^ signMask128H, l}
+	}
+	// negative
+	return Float128{^h, ^l}
+}
+
+// sortKey returns the order-preserving key of f as an unsigned integer.
+func (f Float128) sortKey() int128.Uint128 {
+	// comparable maps both -0 and +0 to 0.
+	// subtract 1 from negative numbers to distinguish them.
+	i := f.comparable().Sub(int128.Int128{L: f.h >> 63})
+
+	// convert from signed to unsigned preserving the order.
+	return int128.Uint128{H: uint64(i.H) ^ signMask128H, L: i.L}
+}
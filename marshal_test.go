@@ -0,0 +1,356 @@
+package float128
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestMarshalText(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  string
+	}{
+		{Float128{0x3fff_0000_0000_0000, 0}, "1"},
+		{Float128{signMask128H, 0}, "-0"},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, "0.1"},
+		{Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, "1.189731495357231765085759326628007e+4932"},
+		{Inf(1), "+Inf"},
+		{Inf(-1), "-Inf"},
+		{NaN(), "NaN"},
+	}
+	for _, tt := range tests {
+		got, err := tt.input.MarshalText()
+		if err != nil {
+			t.Errorf("%s.MarshalText() returns error: %v", dump(tt.input), err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s.MarshalText() = %q, want %q", dump(tt.input), got, tt.want)
+		}
+
+		var f Float128
+		if err := f.UnmarshalText(got); err != nil {
+			t.Errorf("UnmarshalText(%q) returns error: %v", got, err)
+			continue
+		}
+		if !equals(f, tt.input) {
+			t.Errorf("UnmarshalText(%q) = %s, want %s", got, dump(f), dump(tt.input))
+		}
+	}
+}
+
+func TestUnmarshalText_Error(t *testing.T) {
+	var f Float128
+	if err := f.UnmarshalText([]byte("1.2.3")); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	tests := []struct {
+		input      Float128
+		wantNumber string
+		wantString string
+	}{
+		{Float128{0x3fff_0000_0000_0000, 0}, `1`, `"1"`},
+		{Float128{signMask128H, 0}, `-0`, `"-0"`},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, `0.1`, `"0.1"`},
+		{Float128{0x4000_921f_b544_42d1, 0x8469_898c_c517_01b8}, `3.1415926535897932384626433832795028`, `"3.1415926535897932384626433832795028"`},
+		{Float128{0, 1}, `6e-4966`, `"6e-4966"`},
+		{Inf(1), `"+Inf"`, `"+Inf"`},
+		{Inf(-1), `"-Inf"`, `"-Inf"`},
+		{NaN(), `"NaN"`, `"NaN"`},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.input)
+		if err != nil {
+			t.Errorf("json.Marshal(%s) returns error: %v", dump(tt.input), err)
+			continue
+		}
+		if string(got) != tt.wantNumber {
+			t.Errorf("json.Marshal(%s) = %s, want %s", dump(tt.input), got, tt.wantNumber)
+		}
+
+		got, err = json.Marshal(JSONString(tt.input))
+		if err != nil {
+			t.Errorf("json.Marshal(JSONString(%s)) returns error: %v", dump(tt.input), err)
+			continue
+		}
+		if string(got) != tt.wantString {
+			t.Errorf("json.Marshal(JSONString(%s)) = %s, want %s", dump(tt.input), got, tt.wantString)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Float128
+	}{
+		{`1`, Float128{0x3fff_0000_0000_0000, 0}},
+		{`-0`, Float128{signMask128H, 0}},
+		{`0.1`, Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}},
+		{`1E+0`, Float128{0x3fff_0000_0000_0000, 0}},
+		{`"0.1"`, Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}},
+		{`"0x1.999999999999999999999999999ap-4"`, Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}},
+		{`"+Inf"`, Inf(1)},
+		{`"-Infinity"`, Inf(-1)},
+		{`"NaN"`, NaN()},
+	}
+	for _, tt := range tests {
+		var got Float128
+		if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+			t.Errorf("json.Unmarshal(%s) returns error: %v", tt.input, err)
+			continue
+		}
+		if !equals(got, tt.want) {
+			t.Errorf("json.Unmarshal(%s) = %s, want %s", tt.input, dump(got), dump(tt.want))
+		}
+
+		var s JSONString
+		if err := json.Unmarshal([]byte(tt.input), &s); err != nil {
+			t.Errorf("json.Unmarshal(%s) returns error: %v", tt.input, err)
+			continue
+		}
+		if !equals(Float128(s), tt.want) {
+			t.Errorf("json.Unmarshal(%s) = %s, want %s", tt.input, dump(Float128(s)), dump(tt.want))
+		}
+	}
+}
+
+func TestUnmarshalJSON_Null(t *testing.T) {
+	v := struct {
+		F Float128
+	}{
+		F: Float128{0x3fff_0000_0000_0000, 0},
+	}
+	if err := json.Unmarshal([]byte(`{"F":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Float128{0x3fff_0000_0000_0000, 0}); !equals(v.F, want) {
+		t.Errorf("v.F = %s, want %s", dump(v.F), dump(want))
+	}
+}
+
+func TestUnmarshalJSON_Error(t *testing.T) {
+	tests := []string{
+		`true`,
+		`{}`,
+		`[]`,
+		`""`,
+		`"1.2.3"`,
+		`1e5000`,
+	}
+	for _, input := range tests {
+		var f Float128
+		if err := json.Unmarshal([]byte(input), &f); err == nil {
+			t.Errorf("json.Unmarshal(%s) returns nil, want error", input)
+		}
+	}
+}
+
+func TestMarshalJSON_RoundTrip(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 1000
+	if testing.Short() {
+		n = 100
+	}
+	for i := 0; i < n; i++ {
+		a, b := r.Float128Pair()
+		v := struct {
+			A Float128
+			B JSONString
+		}{a, JSONString(b)}
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Errorf("json.Marshal(%s, %s) returns error: %v", dump(a), dump(b), err)
+			continue
+		}
+
+		var got struct {
+			A Float128
+			B JSONString
+		}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Errorf("json.Unmarshal(%s) returns error: %v", data, err)
+			continue
+		}
+		if !equals(got.A, a) {
+			t.Errorf("A: got %s, want %s", dump(got.A), dump(a))
+		}
+		if !equals(Float128(got.B), b) {
+			t.Errorf("B: got %s, want %s", dump(Float128(got.B)), dump(b))
+		}
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	tests := []struct {
+		input Float128
+		le    []byte
+		be    []byte
+	}{
+		{
+			Float128{0x3fff_0000_0000_0000, 0},
+			[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0x3f},
+			[]byte{0x3f, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			Float128{0xc000_921f_b544_42d1, 0x8469_898c_c517_01b8},
+			[]byte{0xb8, 0x01, 0x17, 0xc5, 0x8c, 0x89, 0x69, 0x84, 0xd1, 0x42, 0x44, 0xb5, 0x1f, 0x92, 0x00, 0xc0},
+			[]byte{0xc0, 0x00, 0x92, 0x1f, 0xb5, 0x44, 0x42, 0xd1, 0x84, 0x69, 0x89, 0x8c, 0xc5, 0x17, 0x01, 0xb8},
+		},
+	}
+	for _, tt := range tests {
+		got, err := tt.input.MarshalBinary()
+		if err != nil {
+			t.Errorf("%s.MarshalBinary() returns error: %v", dump(tt.input), err)
+			continue
+		}
+		if !bytes.Equal(got, tt.le) {
+			t.Errorf("%s.MarshalBinary() = %x, want %x", dump(tt.input), got, tt.le)
+		}
+
+		got, err = tt.input.AppendBinary([]byte{0x01})
+		if err != nil {
+			t.Errorf("%s.AppendBinary() returns error: %v", dump(tt.input), err)
+			continue
+		}
+		if !bytes.Equal(got, append([]byte{0x01}, tt.le...)) {
+			t.Errorf("%s.AppendBinary() = %x, want 01%x", dump(tt.input), got, tt.le)
+		}
+
+		buf := make([]byte, 16)
+		tt.input.PutBytesLE(buf)
+		if !bytes.Equal(buf, tt.le) {
+			t.Errorf("%s.PutBytesLE() = %x, want %x", dump(tt.input), buf, tt.le)
+		}
+		tt.input.PutBytesBE(buf)
+		if !bytes.Equal(buf, tt.be) {
+			t.Errorf("%s.PutBytesBE() = %x, want %x", dump(tt.input), buf, tt.be)
+		}
+
+		if f := FromBytesLE(tt.le); f != tt.input {
+			t.Errorf("FromBytesLE(%x) = %s, want %s", tt.le, dump(f), dump(tt.input))
+		}
+		if f := FromBytesBE(tt.be); f != tt.input {
+			t.Errorf("FromBytesBE(%x) = %s, want %s", tt.be, dump(f), dump(tt.input))
+		}
+
+		var f Float128
+		if err := f.UnmarshalBinary(tt.le); err != nil {
+			t.Errorf("UnmarshalBinary(%x) returns error: %v", tt.le, err)
+			continue
+		}
+		if f != tt.input {
+			t.Errorf("UnmarshalBinary(%x) = %s, want %s", tt.le, dump(f), dump(tt.input))
+		}
+	}
+}
+
+func TestUnmarshalBinary_Error(t *testing.T) {
+	var f Float128
+	if err := f.UnmarshalBinary(make([]byte, 15)); err == nil {
+		t.Error("want error, got nil")
+	}
+	if err := f.UnmarshalBinary(make([]byte, 17)); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestGob(t *testing.T) {
+	type T struct {
+		A Float128
+		B []Float128
+	}
+	want := T{
+		A: Float128{0xc000_921f_b544_42d1, 0x8469_898c_c517_01b8},
+		B: []Float128{{0, 1}, NaN(), Inf(-1), {signMask128H, 0}},
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(want); err != nil {
+		t.Fatal(err)
+	}
+	var got T
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.A != want.A {
+		t.Errorf("A: got %s, want %s", dump(got.A), dump(want.A))
+	}
+	if len(got.B) != len(want.B) {
+		t.Fatalf("len(B): got %d, want %d", len(got.B), len(want.B))
+	}
+	for i := range want.B {
+		if got.B[i] != want.B[i] {
+			t.Errorf("B[%d]: got %s, want %s", i, dump(got.B[i]), dump(want.B[i]))
+		}
+	}
+}
+
+func TestAppendSortKey(t *testing.T) {
+	// in ascending order
+	inputs := []Float128{
+		{0xffff_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, // -NaN with the largest payload
+		{0xffff_8000_0000_0000, 0},                     // -NaN
+		{0xffff_0000_0000_0001, 0},                     // -sNaN
+		Inf(-1),
+		{0xfffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, // the smallest finite number
+		{0xbfff_0000_0000_0000, 0x0000_0000_0000_0001}, // -1.0000000000000000000000000000000002
+		{0xbfff_0000_0000_0000, 0},                     // -1
+		{0x8001_0000_0000_0000, 0},                     // the largest negative normal number
+		{0x8000_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, // the smallest negative subnormal number
+		{0x8000_0000_0000_0000, 1},                     // the largest negative subnormal number
+		{signMask128H, 0},                              // -0
+		{0, 0},                                         // +0
+		{0, 1},                                         // the smallest positive subnormal number
+		{0x3fff_0000_0000_0000, 0},                     // 1
+		{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, // the largest finite number
+		Inf(1),
+		{0x7fff_0000_0000_0001, 0}, // sNaN
+		{0x7fff_8000_0000_0000, 0}, // NaN
+		{0x7fff_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, // NaN with the largest payload
+	}
+
+	var prev []byte
+	for i, f := range inputs {
+		key := f.AppendSortKey(nil)
+		if len(key) != 16 {
+			t.Errorf("len(%s.AppendSortKey(nil)) = %d, want 16", dump(f), len(key))
+			continue
+		}
+		if i > 0 && bytes.Compare(prev, key) >= 0 {
+			t.Errorf("key of %s must be less than key of %s: %x, %x", dump(inputs[i-1]), dump(f), prev, key)
+		}
+		if got := FromSortKey(key); got != f {
+			t.Errorf("FromSortKey(%x) = %s, want %s", key, dump(got), dump(f))
+		}
+		prev = key
+	}
+}
+
+func TestAppendSortKey_Random(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 100000
+	if testing.Short() {
+		n = 1000
+	}
+	for i := 0; i < n; i++ {
+		a, b := r.Float128Pair()
+		ka := a.AppendSortKey(nil)
+		kb := b.AppendSortKey([]byte{})
+		if got := FromSortKey(ka); got != a {
+			t.Errorf("FromSortKey(%x) = %s, want %s", ka, dump(got), dump(a))
+		}
+		if a.IsNaN() || b.IsNaN() {
+			continue
+		}
+		c := bytes.Compare(ka, kb)
+		if a.Lt(b) && c >= 0 || a.Gt(b) && c <= 0 {
+			t.Errorf("the order of keys doesn't match: %s, %s", dump(a), dump(b))
+		}
+	}
+}
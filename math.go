@@ -1,6 +1,8 @@
 package float128
 
 import (
+	"math/big"
+
 	"github.com/shogo82148/int128"
 )
 
@@ -29,6 +31,195 @@ func (f Float128) split() (sign uint64, exp int32, frac int128.Uint128) {
 	return
 }
 
+// pack returns the floating point number nearest to (-1)^sign × frac × 2^exp,
+// rounding ties to even.
+// sticky must be non-zero if the exact value has any non-zero bits below frac.
+func pack(sign uint64, exp int32, frac int128.Uint128, sticky uint64) Float128 {
+	f, _, _ := packRound(sign, exp, frac, sticky, ToNearestEven)
+	return f
+}
+
+// packRound returns the floating point number (-1)^sign × frac × 2^exp rounded in the rounding mode,
+// the accuracy of the result, and the raised exception flags.
+// sticky must be non-zero if the exact value has any non-zero bits below frac.
+func packRound(sign uint64, exp int32, frac int128.Uint128, sticky uint64, mode RoundingMode) (Float128, big.Accuracy, Flags) {
+	b, acc, flags := binary128.round(sign, exp, frac, sticky, mode)
+	return Float128{sign | b.H, b.L}, acc, flags
+}
+
+// packRound256 is the same as packRound, but frac is 256 bits.
+func packRound256(sign uint64, exp int32, frac uint256, mode RoundingMode) (Float128, big.Accuracy, Flags) {
+	n := frac.leadingZeros()
+	frac = frac.lsh(uint(n))
+	exp += 128 - int32(n)
+	return packRound(sign, exp, int128.Uint128{H: frac.a, L: frac.b}, squash64(frac.c|frac.d), mode)
+}
+
+// floatFormat describes a binary interchange format of IEEE 754.
+type floatFormat struct {
+	prec uint  // the precision in bits, including the implicit leading bit
+	emax int32 // the maximum exponent, which is equal to the bias
+}
+
+var (
+	binary32  = floatFormat{prec: shift32 + 1, emax: bias32}
+	binary64  = floatFormat{prec: shift64 + 1, emax: bias64}
+	binary128 = floatFormat{prec: shift128 + 1, emax: bias128}
+)
+
+// round returns the binary representation of (-1)^sign × frac × 2^exp rounded to ff in the rounding mode,
+// the accuracy of the result, and the raised exception flags.
+// The sign bit is not included in the result.
+// sticky must be non-zero if the exact value has any non-zero bits below frac.
+//
+// The underflow is detected after rounding, in the same way as x86 processors.
+func (ff floatFormat) round(sign uint64, exp int32, frac int128.Uint128, sticky uint64, mode RoundingMode) (int128.Uint128, big.Accuracy, Flags) {
+	if frac.H|frac.L == 0 {
+		if sticky == 0 {
+			return int128.Uint128{}, big.Exact, 0
+		}
+		// the exact value is far below the smallest subnormal number.
+		// move sticky into frac to round it.
+		frac, exp = one, -(ff.emax+int32(ff.prec))*2
+		sticky = 0
+	}
+
+	// normalize frac so that its most significant bit is bit 127.
+	n := frac.LeadingZeros()
+	frac = frac.Lsh(uint(n))
+	exp -= int32(n)
+
+	infBits := int128.Uint128{L: uint64(ff.emax)*2 + 1}.Lsh(ff.prec - 1)
+	e := exp + 127 // the exponent of the leading bit
+	if e > ff.emax {
+		// overflow
+		if roundsToInf(sign, mode) {
+			return infBits, above(sign), FlagOverflow | FlagInexact
+		}
+		// the largest finite number
+		return infBits.Sub(one), below(sign), FlagOverflow | FlagInexact
+	}
+
+	// the exponent of the least significant bit of the result
+	emin := 1 - ff.emax
+	lsb := e - int32(ff.prec-1)
+	if lsb < emin-int32(ff.prec-1) {
+		// the result is subnormal
+		lsb = emin - int32(ff.prec-1)
+	}
+
+	q, acc := roundShift(sign, frac, sticky, uint(lsb-exp), mode)
+
+	var flags Flags
+	if acc != big.Exact {
+		flags |= FlagInexact
+		if e < emin {
+			// the result is tiny if it is still below the smallest normal number
+			// after rounding with unbounded exponent range.
+			tiny := true
+			if e == emin-1 {
+				q, _ := roundShift(sign, frac, sticky, 128-ff.prec, mode)
+				tiny = q.Len() <= int(ff.prec)
+			}
+			if tiny {
+				flags |= FlagUnderflow
+			}
+		}
+	}
+
+	// the carry of rounding propagates into the exponent,
+	// which turns subnormal numbers into normal ones and the largest finite numbers into infinities.
+	q = q.Add(int128.Uint128{L: uint64(lsb + ff.emax + int32(ff.prec) - 2)}.Lsh(ff.prec - 1))
+	if q == infBits {
+		flags |= FlagOverflow
+	}
+	return q, acc, flags
+}
+
+// roundShift returns frac >> shift rounded in the rounding mode, and the accuracy of the result.
+// sticky must be non-zero if the exact value has any non-zero bits below frac.
+// frac and shift must be non-zero.
+func roundShift(sign uint64, frac int128.Uint128, sticky uint64, shift uint, mode RoundingMode) (int128.Uint128, big.Accuracy) {
+	var q, rem, half int128.Uint128
+	if shift > 128 {
+		// frac >> shift is less than half.
+		rem, half = int128.Uint128{}, int128.Uint128{H: 1 << 63}
+		sticky = 1
+	} else if shift == 128 {
+		rem, half = frac, int128.Uint128{H: 1 << 63}
+	} else if shift < 64 {
+		q = frac.Rsh(shift)
+		rem = int128.Uint128{L: frac.L & (1<<shift - 1)}
+		half = int128.Uint128{L: 1 << (shift - 1)}
+	} else {
+		q = frac.Rsh(shift)
+		rem = frac.And(one.Lsh(shift).Sub(one))
+		half = one.Lsh(shift - 1)
+	}
+	if rem.H|rem.L|sticky == 0 {
+		return q, big.Exact
+	}
+
+	c := rem.Cmp(half)
+	if c == 0 && sticky != 0 {
+		c = 1
+	}
+	if roundsUp(sign, q.L&1 != 0, c, mode) {
+		return q.Add(one), above(sign)
+	}
+	return q, below(sign)
+}
+
+// roundsUp reports whether the inexact result is rounded up in magnitude in the rounding mode.
+// odd reports whether the truncated result is odd,
+// and c is the result of comparing the discarded fraction with the half of the unit in the last place.
+func roundsUp(sign uint64, odd bool, c int, mode RoundingMode) bool {
+	switch mode {
+	case ToNearestEven:
+		return c > 0 || c == 0 && odd
+	case ToNearestAway:
+		return c >= 0
+	case AwayFromZero:
+		return true
+	case ToNegativeInf:
+		return sign != 0
+	case ToPositiveInf:
+		return sign == 0
+	case ToOdd:
+		return !odd
+	}
+	return false
+}
+
+// roundsToInf reports whether the overflowed results round to infinity in the rounding mode.
+func roundsToInf(sign uint64, mode RoundingMode) bool {
+	switch mode {
+	case ToZero, ToOdd:
+		return false
+	case ToNegativeInf:
+		return sign != 0
+	case ToPositiveInf:
+		return sign == 0
+	}
+	return true
+}
+
+// above returns the accuracy of the result whose magnitude is larger than the exact value.
+func above(sign uint64) big.Accuracy {
+	if sign != 0 {
+		return big.Below
+	}
+	return big.Above
+}
+
+// below returns the accuracy of the result whose magnitude is smaller than the exact value.
+func below(sign uint64) big.Accuracy {
+	if sign != 0 {
+		return big.Above
+	}
+	return big.Below
+}
+
 func (a Float128) Mul(b Float128) Float128 {
 	if a.IsNaN() || b.IsNaN() {
 		return propagateNaN(a, b)
@@ -224,70 +415,15 @@ func (a Float128) Add(b Float128) Float128 {
 		return b
 	}
 
-	if expA < expB {
-		signA, signB = signB, signA
-		expA, expB = expB, expA
-		fracA, fracB = fracB, fracA
-	}
-	exp := expA
-
-	// align the fraction
-	const margin = 4
-	fracA256 := uint256{a: fracA.H, b: fracA.L, c: 0, d: 0}.rsh(margin)
-	fracB256 := uint256{a: fracB.H, b: fracB.L, c: 0, d: 0}.rsh(margin)
-	fracB256 = fracB256.rsh(uint(expA - expB))
-
-	// log.Printf("fracA256 = %#v", fracA256)
-	// log.Printf("fracB256 = %#v", fracB256)
-
-	// do addition
-	ifracA256 := fracA256.int256().setSign(signA != 0)
-	ifracB256 := fracB256.int256().setSign(signB != 0)
-	ifrac256 := ifracA256.add(ifracB256)
-
-	// split into sign and absolute value
-	sign := uint64(ifrac256.a) & signMask128H
-	frac256 := ifrac256.abs()
-
-	// normalize
-	var shift int32
-	shift = int32(frac256.leadingZeros() - 19)
-
-	if frac256.isZero() || exp-shift < -(bias128+shift128) {
-		// underflow
-		return Float128{sign, 0}
-	} else if exp-shift <= -bias128 {
-		// the result is subnormal
-		shift = 1 - (exp + bias128)
-		// offset := one.Lsh(uint(shift) + 1).Sub(one).Add(frac256.rsh(uint(shift) + 2).and(one))
-		// frac256 = frac256.add(offset) // round to nearest even
-		// log.Printf(" frac256 = %#v >> %d", frac256, shift-margin+64)
-		frac256 = frac256.rsh(uint(shift - margin + 64))
-		// log.Printf(" frac256 = %#v", frac256)
-		return Float128{sign | frac256.b, frac256.c}
-	}
-
-	one := uint256{a: 0, b: 0, c: 0, d: 1}
-	ff := uint256{0, 0, 0x7fff_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}
-	// log.Printf("           %#v", frac256.rsh(128-uint(shift+margin)))
-	ff = ff.add(frac256.rsh(128 - uint(shift+margin)).and(one)) // round to nearest even
-	frac256 = frac256.add(ff.rsh(uint(shift + margin)))
-	shift = int32(frac256.leadingZeros() - 19)
-	// log.Printf(" frac256 = %#v << %d", frac256, shift+4)
-	frac256 = frac256.lsh(uint(shift + margin))
-	// log.Printf(" frac256 = %#v", frac256)
-
-	exp -= shift
-	exp += bias128
-	if exp >= mask128 {
-		// overflow
-		return Float128{sign | inf.h, inf.l}
-	}
-
-	return Float128{sign | uint64(exp)<<(shift128-64) | (frac256.a & fracMask128H), frac256.b}
+	f, _ := addRound128(signA, expA, fracA, signB, expB, fracB, ToNearestEven)
+	return f
 }
 
 func (a Float128) Sub(b Float128) Float128 {
+	if b.IsNaN() {
+		// don't change the sign of NaN
+		return propagateNaN(a, b)
+	}
 	return a.Add(b.Neg())
 }
 
@@ -316,6 +452,36 @@ func (a Float128) Compare(b Float128) int {
 	return ia.Cmp(ib)
 }
 
+// CompareTotal compares a and b in the totalOrder of IEEE 754 and returns:
+//
+//	-1 if a <  b
+//	 0 if a and b have the same bit pattern
+//	+1 if a >  b
+//
+// The order is:
+//
+//	-NaN < -Inf < negative numbers < -0 < +0 < positive numbers < +Inf < +NaN
+//
+// Negative NaNs are ordered by their payloads in reverse, and positive NaNs are ordered by their payloads.
+// Signaling NaNs are closer to zero than quiet NaNs of the same sign.
+// Unlike [Float128.Compare], it is a strict weak ordering for all bit patterns,
+// so it can be used with [slices.SortFunc].
+func (a Float128) CompareTotal(b Float128) int {
+	return a.sortKey().Cmp(b.sortKey())
+}
+
+// TotalOrder reports whether a <= b in the totalOrder of IEEE 754.
+// See [Float128.CompareTotal] for the order.
+func (a Float128) TotalOrder(b Float128) bool {
+	return a.CompareTotal(b) <= 0
+}
+
+// TotalOrderMag reports whether |a| <= |b| in the totalOrder of IEEE 754.
+// It is the same as a.Abs().TotalOrder(b.Abs()).
+func (a Float128) TotalOrderMag(b Float128) bool {
+	return a.Abs().CompareTotal(b.Abs()) <= 0
+}
+
 // comparable returns a comparable format for a.
 func (a Float128) comparable() int128.Int128 {
 	i := int128.Int128{H: int64(a.h), L: a.l}
@@ -390,7 +556,8 @@ func (f Float128) Neg() Float128 {
 func FMA(x, y, z Float128) Float128 {
 	// handling NaN
 	if x.IsNaN() || y.IsNaN() || z.IsNaN() {
-		return nan
+		f, _ := FMAFlags(x, y, z, ToNearestEven)
+		return f
 	}
 
 	// Inf involved. At most one rounding will occur.
@@ -403,22 +570,8 @@ func FMA(x, y, z Float128) Float128 {
 		return z
 	}
 
-	signA, expA, fracA := x.split()
-	signB, expB, fracB := y.split()
-	signC, expC, fracC := z.split()
-	sign := signA ^ signB
-
-	// add guard and round bits
-	fracA = fracA.Lsh(4)
-	fracB = fracB.Lsh(4)
-	fracC256 := uint256{a: fracC.H, b: fracC.L, c: 0, d: 0}.rsh(8)
-	// log.Printf(" fracC256 = %#v", fracC256)
-
-	// calculate a * b
-	exp := expA + expB
-	frac256 := mul128(fracA, fracB)
-	// log.Printf("  frac256 = %#v", frac256)
-	if frac256.isZero() {
+	sign := (x.h ^ y.h) & signMask128H
+	if x.isZero() || y.isZero() {
 		// +0 + ±0 = +0
 		// -0 + ±0 = ±0
 		if z.isZero() {
@@ -429,74 +582,17 @@ func FMA(x, y, z Float128) Float128 {
 		return z
 	}
 
-	// add c
-	if expC <= exp {
-		one := uint256{a: 0, b: 0, c: 0, d: 1}
-		ff := one.lsh(uint(exp - expC)).sub(one)
-		// log.Printf("        ff: %#v", ff)
-		ff = fracC256.and(ff)
-		fracC256 = fracC256.rsh(uint(exp - expC))
-		fracC256.d |= squash64(ff.a) | squash64(ff.b) | squash64(ff.c) | squash64(ff.d)
-	} else {
-		one := uint256{a: 0, b: 0, c: 0, d: 1}
-		ff := one.lsh(uint(expC - exp)).sub(one)
-		ff = frac256.and(ff)
-		frac256 = frac256.rsh(uint(expC - exp))
-		frac256.d |= squash64(ff.a) | squash64(ff.b) | squash64(ff.c) | squash64(ff.d)
-		exp = expC
-	}
-	// log.Printf("  fracC256: %#v", fracC256)
-	// log.Printf("+  frac256: %#v", frac256)
-	ifracC256 := fracC256.int256().setSign(signC != 0)
-	ifrac256 := frac256.int256().setSign(sign != 0)
-	ifrac256 = ifrac256.add(ifracC256)
-	sign = uint64(ifrac256.a) & signMask128H
-	frac256 = ifrac256.abs()
-	// log.Printf("=  frac256: %#v", frac256)
-
-	// normalize
-	// log.Println("leading zero:", frac256.leadingZeros())
-	shift := int32(23 - frac256.leadingZeros())
-	expTmp := exp + shift
-	// log.Println("exp:", exp)
-	if frac256.isZero() || expTmp < -(bias128+shift128) {
-		// underflow
-		return Float128{sign, 0}
-	} else if expTmp <= -bias128 {
-		shift := uint(128 - 7 - (expTmp - shift + bias128))
-		one := uint256{a: 0, b: 0, c: 0, d: 1}
-		ff := one.lsh(shift - 1).sub(one)
-		ff = ff.add(frac256.rsh(shift).and(one)) // round to nearest even
-		frac256 = frac256.add(ff)
-		// log.Printf(" frac256 = %#v >> %d", frac256, shift)
-		frac256 = frac256.rsh(shift)
-		// log.Printf(" frac256 = %#v", frac256)
-
-		return Float128{sign | frac256.c, frac256.d}
-	}
-
-	if 128-8+shift >= 0 {
-		one := uint256{a: 0, b: 0, c: 0, d: 1}
-		ff := one.lsh(uint(128-8+shift) - 1).sub(one)
-		ff = ff.add(frac256.rsh(uint(128 - 8 + shift)).and(one)) // round to nearest even
-		frac256 = frac256.add(ff)
-		shift = int32(23 - frac256.leadingZeros())
-		exp += shift
-		frac256 = frac256.rsh(uint(128 - 8 + shift))
-		// log.Printf(" frac256 = %#v", frac256)
-	} else {
-		shift = int32(23 - frac256.leadingZeros())
-		exp += shift
-		frac256 = frac256.lsh(uint(-128 + 8 - shift))
-	}
-	exp += bias128
-	if exp >= mask128 {
-		// overflow
-		return Float128{sign | inf.h, inf.l}
+	// the exact product fits in 256 bits, so only the sum is rounded.
+	_, expA, fracA := x.split()
+	_, expB, fracB := y.split()
+	exp := expA + expB - 2*shift128
+	frac256 := mul128(fracA, fracB)
+	if z.isZero() {
+		f, _, _ := packRound256(sign, exp, frac256, ToNearestEven)
+		return f
 	}
-	_ = signC
-	_ = expC
-	// log.Println()
 
-	return Float128{sign | uint64(exp)<<(shift128-64) | (frac256.c & fracMask128H), frac256.d}
+	signC, expC, fracC := z.split()
+	f, _ := fmaRound(sign, exp, frac256, signC, expC-shift128, fracC, ToNearestEven)
+	return f
 }
@@ -3,6 +3,7 @@ package float128
 import (
 	"fmt"
 	"runtime"
+	"slices"
 	"testing"
 )
 
@@ -431,6 +432,23 @@ func TestAdd(t *testing.T) {
 			Float128{0xbf8e000000000000, 0x0000000000000000}, // -0x1.0000000000000000000000000000p-113
 			Float128{0x0000000000000000, 0x0000000000000000}, // 0
 		},
+		{
+			Float128{0x0070ffffffffffff, 0xffffffffffffffff}, // +0x1.ffffffffffffffffffffffffffffp-16271
+			Float128{0x8071000000000000, 0x0000000000000000}, // -0x1.0000000000000000000000000000p-16270
+			Float128{0x8000800000000000, 0x0000000000000000}, // -0x0.8000000000000000000000000000p-16382
+		},
+
+		// the sum is subnormal after cancellation
+		{
+			Float128{0x806e2919ee25e707, 0x83c73848ff3beacd}, // -0x1.2919ee25e70783c73848ff3beacdp-16273
+			Float128{0x006e2919ee25e707, 0x83c73848ff3bead4}, // +0x1.2919ee25e70783c73848ff3bead4p-16273
+			Float128{0x0000e00000000000, 0x0000000000000000}, // +0x0.e000000000000000000000000000p-16382
+		},
+		{
+			Float128{0x0056ffffffffffff, 0xffffffffffffff4c}, // +0x1.ffffffffffffffffffffffffff4cp-16297
+			Float128{0x8057000000000000, 0x0000000000000000}, // -0x1.0000000000000000000000000000p-16296
+			Float128{0x8000000016800000, 0x0000000000000000}, // -0x0.0000168000000000000000000000p-16382
+		},
 	}
 
 	for _, tt := range tests {
@@ -449,6 +467,16 @@ func BenchmarkAdd(b *testing.B) {
 	}
 }
 
+func BenchmarkAdd_CloseExp(b *testing.B) {
+	// the operands are aligned, and may cancel each other out.
+	r := newXoshiro256pp()
+	for i := 0; i < b.N; i++ {
+		x, y := r.Float128Pair()
+		y = withExp(y, biasedExp(x)+int32(y.l%64)-32)
+		runtime.KeepAlive(x.Add(y))
+	}
+}
+
 func TestComparison(t *testing.T) {
 	tests := []struct {
 		a, b                   Float128
@@ -516,6 +544,117 @@ func TestComparison(t *testing.T) {
 	}
 }
 
+func TestCompareTotal(t *testing.T) {
+	// in ascending order
+	inputs := []Float128{
+		{0xffff_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, // -NaN with the largest payload
+		{0xffff_8000_0000_0000, 1},                     // -NaN with payload 1
+		{0xffff_8000_0000_0000, 0},                     // -NaN
+		{0xffff_0000_0000_0000, 1},                     // -sNaN
+		Inf(-1),
+		{0xfffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, // the smallest finite number
+		{0xbfff_0000_0000_0000, 0},                     // -1
+		{0x8000_0000_0000_0000, 1},                     // the largest negative subnormal number
+		{0x8000_0000_0000_0000, 0},                     // -0
+		{0x0000_0000_0000_0000, 0},                     // +0
+		{0x0000_0000_0000_0000, 1},                     // the smallest positive subnormal number
+		{0x3fff_0000_0000_0000, 0},                     // 1
+		{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, // the largest finite number
+		Inf(1),
+		{0x7fff_0000_0000_0000, 1}, // sNaN
+		{0x7fff_8000_0000_0000, 0}, // NaN
+		{0x7fff_8000_0000_0000, 1}, // NaN with payload 1
+		{0x7fff_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, // NaN with the largest payload
+	}
+
+	for i, a := range inputs {
+		for j, b := range inputs {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := a.CompareTotal(b); got != want {
+				t.Errorf("%s.CompareTotal(%s) = %d, want %d", dump(a), dump(b), got, want)
+			}
+			if got := a.TotalOrder(b); got != (i <= j) {
+				t.Errorf("%s.TotalOrder(%s) = %t, want %t", dump(a), dump(b), got, i <= j)
+			}
+		}
+	}
+
+	// TotalOrderMag ignores the signs.
+	tests := []struct {
+		a, b Float128
+		want bool
+	}{
+		{Float128{0x8000_0000_0000_0000, 0}, Float128{0x0000_0000_0000_0000, 0}, true},  // |-0| <= |+0|
+		{Float128{0x0000_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 0}, true},  // |+0| <= |-0|
+		{Float128{0xbfff_0000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 0}, true},  // |-1| <= |1|
+		{Float128{0xc000_0000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 0}, false}, // |-2| <= |1|
+		{Float128{0xffff_8000_0000_0000, 0}, Inf(1), false},                             // |-NaN| <= |+Inf|
+		{Inf(-1), Float128{0xffff_0000_0000_0000, 1}, true},                             // |-Inf| <= |-sNaN|
+		{Float128{0xffff_8000_0000_0000, 0}, Float128{0x7fff_0000_0000_0000, 1}, false}, // |-NaN| <= |sNaN|
+	}
+	for _, tt := range tests {
+		if got := tt.a.TotalOrderMag(tt.b); got != tt.want {
+			t.Errorf("%s.TotalOrderMag(%s) = %t, want %t", dump(tt.a), dump(tt.b), got, tt.want)
+		}
+	}
+}
+
+func TestCompareTotal_Random(t *testing.T) {
+	r := newXoshiro256pp()
+	for i := 0; i < 100000; i++ {
+		a, b := r.Float128Pair()
+		c := a.CompareTotal(b)
+		if got := b.CompareTotal(a); got != -c {
+			t.Errorf("%s.CompareTotal(%s) = %d, %s.CompareTotal(%s) = %d", dump(a), dump(b), c, dump(b), dump(a), got)
+		}
+		if (c == 0) != (a == b) {
+			t.Errorf("%s.CompareTotal(%s) = %d", dump(a), dump(b), c)
+		}
+
+		// it is consistent with Compare for the numbers that Compare distinguishes.
+		if !a.IsNaN() && !b.IsNaN() {
+			if cmp := a.Compare(b); cmp != 0 && cmp != c {
+				t.Errorf("%s.CompareTotal(%s) = %d, but Compare returns %d", dump(a), dump(b), c, cmp)
+			}
+		}
+	}
+}
+
+func TestCompareTotal_SortFunc(t *testing.T) {
+	inputs := []Float128{
+		NaN(),
+		{0x3fff_0000_0000_0000, 0}, // 1
+		{0x0000_0000_0000_0000, 0}, // +0
+		Inf(-1),
+		{0xffff_8000_0000_0000, 0}, // -NaN
+		{0x8000_0000_0000_0000, 0}, // -0
+		{0xbfff_0000_0000_0000, 0}, // -1
+		Inf(1),
+	}
+	want := []Float128{
+		{0xffff_8000_0000_0000, 0}, // -NaN
+		Inf(-1),
+		{0xbfff_0000_0000_0000, 0}, // -1
+		{0x8000_0000_0000_0000, 0}, // -0
+		{0x0000_0000_0000_0000, 0}, // +0
+		{0x3fff_0000_0000_0000, 0}, // 1
+		Inf(1),
+		NaN(),
+	}
+
+	slices.SortFunc(inputs, Float128.CompareTotal)
+	for i := range want {
+		if inputs[i] != want[i] {
+			t.Errorf("inputs[%d] = %s, want %s", i, dump(inputs[i]), dump(want[i]))
+		}
+	}
+}
+
 func BenchmarkEq(b *testing.B) {
 	r := newXoshiro256pp()
 	for i := 0; i < b.N; i++ {
@@ -623,6 +762,40 @@ func TestFMA(t *testing.T) {
 			Float128{0x8000000000000000, 0x0000000000000000}, // -0
 		},
 
+		// z is the negated product, and the result is its rounding error.
+		{
+			Float128{0x335037fdbec4a31e, 0xfea5f3cc7ef5cacf},
+			Float128{0x24cc7e9b26128442, 0xe539e4b5bd2486b1},
+			Float128{0x981dd249b7b227cf, 0xfaeefeef2fef42d7},
+			Float128{0x17a50728c3ff3930, 0x57ac7ec835931f00},
+		},
+		{
+			Float128{0xa2c9d39094e26397, 0x9d156e84d862476c},
+			Float128{0x3cd5c85bb55b634b, 0x0d922805c3ae490c},
+			Float128{0x1fa0a0c0846a2871, 0x38a788321f1ddcf4},
+			Float128{0x9f28dd86fa7fafca, 0xa7a21efd30928800},
+		},
+
+		// z only works as a sticky bit, and it must not cancel the least significant bit of the product.
+		{
+			Float128{0xa3f400000001ffff, 0xffffffffffffffff},
+			Float128{0x4056fffffffdffff, 0xffffffffffffffff},
+			Float128{0x1899ffffffffffff, 0xffffffffffffffff},
+			Float128{0xa44c00000000ffff, 0xfffdffffffffffff},
+		},
+		{
+			Float128{0xe7d10007ffffffff, 0xffffffffffffffff},
+			Float128{0x549dfff7ffffffff, 0xffffffffffffffff},
+			Float128{0x0f7d0001ffffffff, 0xffffffffffffffff},
+			Float128{0xfc700003ffdfffff, 0xffffffffffffffff},
+		},
+		{
+			Float128{0x3fdb000000000007, 0xffffffffffffffff},
+			Float128{0x6beffffffffffff7, 0xffffffffffffffff},
+			Float128{0xc0103b6789ea766e, 0x018c1ec63db8febf},
+			Float128{0x6bcc000000000003, 0xffffffffffdfffff},
+		},
+
 		// random tests
 		{
 			Float128{0x0002010000000000, 0x1fffffffffffffff},
@@ -666,6 +839,18 @@ func TestFMA(t *testing.T) {
 			Float128{0x3ffeffffffefffff, 0xfffffffffffffbff},
 			Float128{0xbf7b000000000000, 0x0000000010000000},
 		},
+		{
+			Float128{0x2cb4ee14e68e0872, 0x2af3339e6d412853},
+			Float128{0xe89e1c95a1d866cd, 0x1fe8eb87774ba59b},
+			Float128{0x5554129ffd264789, 0xd2b78158822141cc},
+			Float128{0x54dc70e3b7605b3a, 0x5771073a9f0b5f80},
+		},
+		{
+			Float128{0x61c5fdffffffffff, 0xffffffffffffffff},
+			Float128{0x43e2000000000000, 0x000007ffffffffff},
+			Float128{0xe5a8fe0000000000, 0x00000feffffffffd},
+			Float128{0x6530ffffffffffff, 0xfff8000000000100},
+		},
 	}
 	for _, tt := range tests {
 		got := FMA(tt.x, tt.y, tt.z)
@@ -683,3 +868,15 @@ func BenchmarkFMA(b *testing.B) {
 		runtime.KeepAlive(FMA(a, b, c))
 	}
 }
+
+func BenchmarkFMA_CloseExp(b *testing.B) {
+	// z is aligned to the product, and may cancel it out.
+	r := newXoshiro256pp()
+	for i := 0; i < b.N; i++ {
+		x, y := r.Float128Pair()
+		z, _ := r.Float128Pair()
+		y = withExp(y, bias128-biasedExp(x)+int32(y.l%64))
+		z = withExp(z, bias128+int32(z.l%256)-128)
+		runtime.KeepAlive(FMA(x, y, z))
+	}
+}
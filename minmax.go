@@ -0,0 +1,190 @@
+package float128
+
+// Min returns the smallest of x and y, as minimum of IEEE 754-2019.
+// Like the built-in min function for float64, -0 is less than +0,
+// and if any argument is NaN, the result is NaN.
+// The result is the first NaN quieted, preserving its sign and payload.
+//
+// Min(s[0], s[1:]...) returns the smallest element of s.
+func Min(x Float128, y ...Float128) Float128 {
+	x = quietNaN(x)
+	for _, v := range y {
+		x = minimum(x, v)
+	}
+	return x
+}
+
+// Max returns the largest of x and y, as maximum of IEEE 754-2019.
+// Like the built-in max function for float64, +0 is greater than -0,
+// and if any argument is NaN, the result is NaN.
+// The result is the first NaN quieted, preserving its sign and payload.
+//
+// Max(s[0], s[1:]...) returns the largest element of s.
+func Max(x Float128, y ...Float128) Float128 {
+	x = quietNaN(x)
+	for _, v := range y {
+		x = maximum(x, v)
+	}
+	return x
+}
+
+// MinNum returns the smallest of x and y, as minimumNumber of IEEE 754-2019.
+// Unlike [Min], NaN arguments are ignored, even if they are signaling.
+// The result is NaN only if all arguments are NaN.
+func MinNum(x Float128, y ...Float128) Float128 {
+	x = quietNaN(x)
+	for _, v := range y {
+		x = minimumNumber(x, v)
+	}
+	return x
+}
+
+// MaxNum returns the largest of x and y, as maximumNumber of IEEE 754-2019.
+// Unlike [Max], NaN arguments are ignored, even if they are signaling.
+// The result is NaN only if all arguments are NaN.
+func MaxNum(x Float128, y ...Float128) Float128 {
+	x = quietNaN(x)
+	for _, v := range y {
+		x = maximumNumber(x, v)
+	}
+	return x
+}
+
+// MinMag returns the argument with the smallest magnitude, as minimumMagnitude of IEEE 754-2019.
+// If the magnitudes are equal, it returns the smaller one, as [Min].
+// If any argument is NaN, the result is NaN.
+func MinMag(x Float128, y ...Float128) Float128 {
+	x = quietNaN(x)
+	for _, v := range y {
+		x = minimumMagnitude(x, v)
+	}
+	return x
+}
+
+// MaxMag returns the argument with the largest magnitude, as maximumMagnitude of IEEE 754-2019.
+// If the magnitudes are equal, it returns the larger one, as [Max].
+// If any argument is NaN, the result is NaN.
+func MaxMag(x Float128, y ...Float128) Float128 {
+	x = quietNaN(x)
+	for _, v := range y {
+		x = maximumMagnitude(x, v)
+	}
+	return x
+}
+
+// MinMagNum is the same as [MinMag], but NaN arguments are ignored,
+// as minimumMagnitudeNumber of IEEE 754-2019.
+func MinMagNum(x Float128, y ...Float128) Float128 {
+	x = quietNaN(x)
+	for _, v := range y {
+		x = minimumMagnitudeNumber(x, v)
+	}
+	return x
+}
+
+// MaxMagNum is the same as [MaxMag], but NaN arguments are ignored,
+// as maximumMagnitudeNumber of IEEE 754-2019.
+func MaxMagNum(x Float128, y ...Float128) Float128 {
+	x = quietNaN(x)
+	for _, v := range y {
+		x = maximumMagnitudeNumber(x, v)
+	}
+	return x
+}
+
+func minimum(x, y Float128) Float128 {
+	if x.IsNaN() || y.IsNaN() {
+		return propagateNaN(x, y)
+	}
+	// the total order of numbers is the same as the numerical order, except -0 < +0.
+	if x.CompareTotal(y) <= 0 {
+		return x
+	}
+	return y
+}
+
+func maximum(x, y Float128) Float128 {
+	if x.IsNaN() || y.IsNaN() {
+		return propagateNaN(x, y)
+	}
+	if x.CompareTotal(y) >= 0 {
+		return x
+	}
+	return y
+}
+
+func minimumNumber(x, y Float128) Float128 {
+	if x.IsNaN() || y.IsNaN() {
+		return ignoreNaN(x, y)
+	}
+	return minimum(x, y)
+}
+
+func maximumNumber(x, y Float128) Float128 {
+	if x.IsNaN() || y.IsNaN() {
+		return ignoreNaN(x, y)
+	}
+	return maximum(x, y)
+}
+
+func minimumMagnitude(x, y Float128) Float128 {
+	if x.IsNaN() || y.IsNaN() {
+		return propagateNaN(x, y)
+	}
+	switch x.Abs().CompareTotal(y.Abs()) {
+	case -1:
+		return x
+	case 1:
+		return y
+	}
+	return minimum(x, y)
+}
+
+func maximumMagnitude(x, y Float128) Float128 {
+	if x.IsNaN() || y.IsNaN() {
+		return propagateNaN(x, y)
+	}
+	switch x.Abs().CompareTotal(y.Abs()) {
+	case -1:
+		return y
+	case 1:
+		return x
+	}
+	return maximum(x, y)
+}
+
+func minimumMagnitudeNumber(x, y Float128) Float128 {
+	if x.IsNaN() || y.IsNaN() {
+		return ignoreNaN(x, y)
+	}
+	return minimumMagnitude(x, y)
+}
+
+func maximumMagnitudeNumber(x, y Float128) Float128 {
+	if x.IsNaN() || y.IsNaN() {
+		return ignoreNaN(x, y)
+	}
+	return maximumMagnitude(x, y)
+}
+
+// quietNaN returns f quieted if f is a signaling NaN, otherwise f.
+// It makes the result of a single argument the same as the result of more arguments.
+func quietNaN(f Float128) Float128 {
+	if f.IsNaN() {
+		return propagateNaN(f, f)
+	}
+	return f
+}
+
+// ignoreNaN returns the non-NaN one of x and y.
+// If both are NaN, it returns the first NaN quieted.
+// x or y must be NaN.
+func ignoreNaN(x, y Float128) Float128 {
+	if !x.IsNaN() {
+		return x
+	}
+	if !y.IsNaN() {
+		return y
+	}
+	return propagateNaN(x, y)
+}
@@ -0,0 +1,209 @@
+package float128
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMinMax(t *testing.T) {
+	one := Float128{0x3fff_0000_0000_0000, 0}
+	negOne := Float128{0xbfff_0000_0000_0000, 0}
+	two := Float128{0x4000_0000_0000_0000, 0}
+	negTwo := Float128{0xc000_0000_0000_0000, 0}
+	zero := Float128{}
+	negZero := Float128{signMask128H, 0}
+	qNaN := Float128{0xffff_8000_0000_0000, 42}    // -NaN with payload 42
+	sNaN := Float128{0x7fff_0000_0000_0000, 43}    // sNaN with payload 43
+	quieted := Float128{0x7fff_8000_0000_0000, 43} // sNaN quieted
+
+	tests := []struct {
+		x, y                 Float128
+		min, max             Float128
+		minNum, maxNum       Float128
+		minMag, maxMag       Float128
+		minMagNum, maxMagNum Float128
+	}{
+		{
+			one, two,
+			one, two,
+			one, two,
+			one, two,
+			one, two,
+		},
+		{
+			negTwo, one,
+			negTwo, one,
+			negTwo, one,
+			one, negTwo,
+			one, negTwo,
+		},
+		{
+			negOne, one,
+			negOne, one,
+			negOne, one,
+			negOne, one,
+			negOne, one,
+		},
+		{
+			zero, negZero,
+			negZero, zero,
+			negZero, zero,
+			negZero, zero,
+			negZero, zero,
+		},
+		{
+			negZero, zero,
+			negZero, zero,
+			negZero, zero,
+			negZero, zero,
+			negZero, zero,
+		},
+		{
+			Inf(-1), Inf(1),
+			Inf(-1), Inf(1),
+			Inf(-1), Inf(1),
+			Inf(-1), Inf(1),
+			Inf(-1), Inf(1),
+		},
+		{
+			Inf(-1), one,
+			Inf(-1), one,
+			Inf(-1), one,
+			one, Inf(-1),
+			one, Inf(-1),
+		},
+
+		// NaN
+		{
+			qNaN, one,
+			qNaN, qNaN,
+			one, one,
+			qNaN, qNaN,
+			one, one,
+		},
+		{
+			one, sNaN,
+			quieted, quieted,
+			one, one,
+			quieted, quieted,
+			one, one,
+		},
+		{
+			sNaN, qNaN,
+			quieted, quieted,
+			quieted, quieted,
+			quieted, quieted,
+			quieted, quieted,
+		},
+		{
+			qNaN, sNaN,
+			qNaN, qNaN,
+			qNaN, qNaN,
+			qNaN, qNaN,
+			qNaN, qNaN,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := Min(tt.x, tt.y); got != tt.min {
+			t.Errorf("Min(%s, %s) = %s, want %s", dump(tt.x), dump(tt.y), dump(got), dump(tt.min))
+		}
+		if got := Max(tt.x, tt.y); got != tt.max {
+			t.Errorf("Max(%s, %s) = %s, want %s", dump(tt.x), dump(tt.y), dump(got), dump(tt.max))
+		}
+		if got := MinNum(tt.x, tt.y); got != tt.minNum {
+			t.Errorf("MinNum(%s, %s) = %s, want %s", dump(tt.x), dump(tt.y), dump(got), dump(tt.minNum))
+		}
+		if got := MaxNum(tt.x, tt.y); got != tt.maxNum {
+			t.Errorf("MaxNum(%s, %s) = %s, want %s", dump(tt.x), dump(tt.y), dump(got), dump(tt.maxNum))
+		}
+		if got := MinMag(tt.x, tt.y); got != tt.minMag {
+			t.Errorf("MinMag(%s, %s) = %s, want %s", dump(tt.x), dump(tt.y), dump(got), dump(tt.minMag))
+		}
+		if got := MaxMag(tt.x, tt.y); got != tt.maxMag {
+			t.Errorf("MaxMag(%s, %s) = %s, want %s", dump(tt.x), dump(tt.y), dump(got), dump(tt.maxMag))
+		}
+		if got := MinMagNum(tt.x, tt.y); got != tt.minMagNum {
+			t.Errorf("MinMagNum(%s, %s) = %s, want %s", dump(tt.x), dump(tt.y), dump(got), dump(tt.minMagNum))
+		}
+		if got := MaxMagNum(tt.x, tt.y); got != tt.maxMagNum {
+			t.Errorf("MaxMagNum(%s, %s) = %s, want %s", dump(tt.x), dump(tt.y), dump(got), dump(tt.maxMagNum))
+		}
+	}
+}
+
+func TestMinMax_Variadic(t *testing.T) {
+	one := Float128{0x3fff_0000_0000_0000, 0}
+	negTwo := Float128{0xc000_0000_0000_0000, 0}
+	negZero := Float128{signMask128H, 0}
+	qNaN := Float128{0x7fff_8000_0000_0000, 42}
+
+	s := []Float128{one, negZero, negTwo, {}}
+	if got := Min(s[0], s[1:]...); got != negTwo {
+		t.Errorf("Min(%v...) = %s, want %s", s, dump(got), dump(negTwo))
+	}
+	if got := Max(s[0], s[1:]...); got != one {
+		t.Errorf("Max(%v...) = %s, want %s", s, dump(got), dump(one))
+	}
+	if got := MinMag(s[0], s[1:]...); got != negZero {
+		t.Errorf("MinMag(%v...) = %s, want %s", s, dump(got), dump(negZero))
+	}
+	if got := MaxMag(s[0], s[1:]...); got != negTwo {
+		t.Errorf("MaxMag(%v...) = %s, want %s", s, dump(got), dump(negTwo))
+	}
+	if got := Min(one); got != one {
+		t.Errorf("Min(%s) = %s, want %s", dump(one), dump(got), dump(one))
+	}
+
+	// a single signaling NaN is quieted.
+	sNaN := Float128{0x7fff_0000_0000_0000, 43}
+	quieted := Float128{0x7fff_8000_0000_0000, 43}
+	for _, fn := range []struct {
+		name string
+		f    func(x Float128, y ...Float128) Float128
+	}{
+		{"Min", Min}, {"Max", Max}, {"MinNum", MinNum}, {"MaxNum", MaxNum},
+		{"MinMag", MinMag}, {"MaxMag", MaxMag}, {"MinMagNum", MinMagNum}, {"MaxMagNum", MaxMagNum},
+	} {
+		if got := fn.f(sNaN); got != quieted {
+			t.Errorf("%s(%s) = %s, want %s", fn.name, dump(sNaN), dump(got), dump(quieted))
+		}
+		if got := fn.f(qNaN); got != qNaN {
+			t.Errorf("%s(%s) = %s, want %s", fn.name, dump(qNaN), dump(got), dump(qNaN))
+		}
+	}
+
+	// NaN in the middle
+	s = []Float128{one, qNaN, negTwo}
+	if got := Min(s[0], s[1:]...); got != qNaN {
+		t.Errorf("Min(%v...) = %s, want %s", s, dump(got), dump(qNaN))
+	}
+	if got := MaxNum(s[0], s[1:]...); got != one {
+		t.Errorf("MaxNum(%v...) = %s, want %s", s, dump(got), dump(one))
+	}
+	if got := MinMagNum(s[0], s[1:]...); got != one {
+		t.Errorf("MinMagNum(%v...) = %s, want %s", s, dump(got), dump(one))
+	}
+}
+
+func TestMinMax_Float64(t *testing.T) {
+	// Min and Max agree with the built-in min and max functions.
+	inputs := []float64{
+		math.Inf(-1), -math.MaxFloat64, -1, -math.SmallestNonzeroFloat64, math.Copysign(0, -1),
+		0, math.SmallestNonzeroFloat64, 1, math.MaxFloat64, math.Inf(1), math.NaN(),
+	}
+
+	for _, a := range inputs {
+		for _, b := range inputs {
+			fa, fb := FromFloat64(a), FromFloat64(b)
+			got, want := Min(fa, fb).Float64(), min(a, b)
+			if math.Float64bits(got) != math.Float64bits(want) && !(math.IsNaN(got) && math.IsNaN(want)) {
+				t.Errorf("Min(%v, %v) = %v, want %v", a, b, got, want)
+			}
+			got, want = Max(fa, fb).Float64(), max(a, b)
+			if math.Float64bits(got) != math.Float64bits(want) && !(math.IsNaN(got) && math.IsNaN(want)) {
+				t.Errorf("Max(%v, %v) = %v, want %v", a, b, got, want)
+			}
+		}
+	}
+}
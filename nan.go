@@ -0,0 +1,97 @@
+package float128
+
+import (
+	"math/big"
+
+	"github.com/shogo82148/int128"
+)
+
+// payloadMask128H is the mask of the payload of NaN, excluding the quiet bit.
+const payloadMask128H = fracMask128H &^ qNaNBitH
+
+// IsSignalingNaN reports whether f is a signaling NaN.
+// A NaN is signaling if the most significant bit of its fraction is zero.
+func (f Float128) IsSignalingNaN() bool {
+	return f.IsNaN() && f.h&qNaNBitH == 0
+}
+
+// QuietNaN returns a positive quiet NaN with the payload.
+// Only the lower 111 bits of payload are used.
+func QuietNaN(payload int128.Uint128) Float128 {
+	return Float128{nan.h | payload.H&payloadMask128H, payload.L}
+}
+
+// SignalingNaN returns a positive signaling NaN with the payload.
+// Only the lower 111 bits of payload are used.
+// SignalingNaN panics if they are zero, because the result would be an infinity.
+func SignalingNaN(payload int128.Uint128) Float128 {
+	f := Float128{inf.h | payload.H&payloadMask128H, payload.L}
+	if !f.IsNaN() {
+		panic("float128: zero payload of signaling NaN")
+	}
+	return f
+}
+
+// GetPayload returns the payload of f as a non-negative integer, as getPayload of IEEE 754.
+// The quiet bit is not a part of the payload.
+// If f is not NaN, GetPayload returns -1.
+func (f Float128) GetPayload() Float128 {
+	if !f.IsNaN() {
+		return Float128{0xbfff_0000_0000_0000, 0} // -1
+	}
+	return FromUint128(int128.Uint128{H: f.h & payloadMask128H, L: f.l})
+}
+
+// SetPayload returns a positive quiet NaN whose payload is x, as setPayload of IEEE 754.
+// If x is not an integer in [0, 2^111), SetPayload returns +0.
+func SetPayload(x Float128) Float128 {
+	payload, ok := x.payload()
+	if !ok {
+		return Float128{}
+	}
+	return QuietNaN(payload)
+}
+
+// SetPayloadSignaling returns a positive signaling NaN whose payload is x, as setPayloadSignaling of IEEE 754.
+// If x is not an integer in [1, 2^111), SetPayloadSignaling returns +0.
+func SetPayloadSignaling(x Float128) Float128 {
+	payload, ok := x.payload()
+	if !ok || payload.H|payload.L == 0 {
+		return Float128{}
+	}
+	return SignalingNaN(payload)
+}
+
+// payload returns x as a payload of NaN.
+// ok is false if x is not an integer in [0, 2^111).
+func (x Float128) payload() (payload int128.Uint128, ok bool) {
+	if x.IsNaN() || x.h&signMask128H != 0 && !x.isZero() {
+		return int128.Uint128{}, false
+	}
+	payload, acc := x.Uint128()
+	if acc != big.Exact || payload.H&^payloadMask128H != 0 {
+		return int128.Uint128{}, false
+	}
+	return payload, true
+}
+
+// propagateNaN returns the first NaN of a and b as a quiet NaN,
+// preserving its sign and payload.
+// a or b must be NaN.
+func propagateNaN(a, b Float128) Float128 {
+	f, _ := propagateNaNFlags(a, b)
+	return f
+}
+
+// propagateNaNFlags is the same as propagateNaN,
+// but it also returns FlagInvalid if a or b is a signaling NaN.
+func propagateNaNFlags(a, b Float128) (Float128, Flags) {
+	var flags Flags
+	if a.IsSignalingNaN() || b.IsSignalingNaN() {
+		flags = FlagInvalid
+	}
+	if !a.IsNaN() {
+		a = b
+	}
+	return Float128{a.h | qNaNBitH, a.l}, flags
+}
@@ -0,0 +1,271 @@
+package float128
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shogo82148/int128"
+)
+
+func TestIsSignalingNaN(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  bool
+	}{
+		{NaN(), false},
+		{Float128{0x7fff_4000_0000_0000, 0}, true},
+		{Float128{0xffff_0000_0000_0000, 1}, true},
+		{Float128{0xffff_8000_0000_0000, 1}, false},
+		{Inf(1), false},
+		{Inf(-1), false},
+		{Float128{0x3fff_0000_0000_0000, 0}, false}, // 1
+	}
+
+	for _, tt := range tests {
+		got := tt.input.IsSignalingNaN()
+		if got != tt.want {
+			t.Errorf("%s.IsSignalingNaN() = %v, want %v", dump(tt.input), got, tt.want)
+		}
+	}
+}
+
+func TestQuietNaN(t *testing.T) {
+	tests := []struct {
+		payload int128.Uint128
+		want    Float128
+	}{
+		{int128.Uint128{}, Float128{0x7fff_8000_0000_0000, 0}},
+		{int128.Uint128{L: 1}, Float128{0x7fff_8000_0000_0000, 1}},
+		{int128.Uint128{H: 0x7fff_ffff_ffff, L: math.MaxUint64}, Float128{0x7fff_ffff_ffff_ffff, math.MaxUint64}},
+
+		// the upper bits are ignored
+		{int128.Uint128{H: math.MaxUint64, L: 0}, Float128{0x7fff_ffff_ffff_ffff, 0}},
+	}
+
+	for _, tt := range tests {
+		got := QuietNaN(tt.payload)
+		if got != tt.want {
+			t.Errorf("QuietNaN(%v) = %s, want %s", tt.payload, dump(got), dump(tt.want))
+		}
+		if got.IsSignalingNaN() {
+			t.Errorf("QuietNaN(%v) is signaling", tt.payload)
+		}
+	}
+}
+
+func TestSignalingNaN(t *testing.T) {
+	tests := []struct {
+		payload int128.Uint128
+		want    Float128
+	}{
+		{int128.Uint128{L: 1}, Float128{0x7fff_0000_0000_0000, 1}},
+		{int128.Uint128{H: 0x7fff_ffff_ffff, L: math.MaxUint64}, Float128{0x7fff_7fff_ffff_ffff, math.MaxUint64}},
+		{int128.Uint128{H: math.MaxUint64, L: 0}, Float128{0x7fff_7fff_ffff_ffff, 0}},
+	}
+
+	for _, tt := range tests {
+		got := SignalingNaN(tt.payload)
+		if got != tt.want {
+			t.Errorf("SignalingNaN(%v) = %s, want %s", tt.payload, dump(got), dump(tt.want))
+		}
+		if !got.IsSignalingNaN() {
+			t.Errorf("SignalingNaN(%v) is not signaling", tt.payload)
+		}
+	}
+
+	t.Run("zero payload", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("SignalingNaN(0) doesn't panic")
+			}
+		}()
+		SignalingNaN(int128.Uint128{H: 0x8000_0000_0000})
+	})
+}
+
+func TestGetPayload(t *testing.T) {
+	tests := []struct {
+		input Float128
+		want  Float128
+	}{
+		{NaN(), Float128{}},
+		{Float128{0xffff_8000_0000_0000, 42}, FromInt64(42)},
+		{Float128{0x7fff_0000_0000_0000, 1}, FromInt64(1)},
+		{Float128{0x7fff_ffff_ffff_ffff, math.MaxUint64}, Float128{0x406d_ffff_ffff_ffff, 0xffff_ffff_ffff_fffc}}, // 2^111-1
+
+		// not NaN
+		{Inf(1), FromInt64(-1)},
+		{Float128{}, FromInt64(-1)},
+	}
+
+	for _, tt := range tests {
+		got := tt.input.GetPayload()
+		if got != tt.want {
+			t.Errorf("%s.GetPayload() = %s, want %s", dump(tt.input), dump(got), dump(tt.want))
+		}
+	}
+}
+
+func TestSetPayload(t *testing.T) {
+	tests := []struct {
+		input     Float128
+		quiet     Float128
+		signaling Float128
+	}{
+		{Float128{}, Float128{0x7fff_8000_0000_0000, 0}, Float128{}},
+		{Float128{signMask128H, 0}, Float128{0x7fff_8000_0000_0000, 0}, Float128{}},
+		{FromInt64(42), Float128{0x7fff_8000_0000_0000, 42}, Float128{0x7fff_0000_0000_0000, 42}},
+		{
+			Float128{0x406d_ffff_ffff_ffff, 0xffff_ffff_ffff_fffc}, // 2^111-1
+			Float128{0x7fff_ffff_ffff_ffff, math.MaxUint64},
+			Float128{0x7fff_7fff_ffff_ffff, math.MaxUint64},
+		},
+
+		// invalid payloads
+		{Float128{0x406e_0000_0000_0000, 0}, Float128{}, Float128{}}, // 2^111
+		{FromInt64(-1), Float128{}, Float128{}},
+		{Float128{0x3ffe_0000_0000_0000, 0}, Float128{}, Float128{}}, // 0.5
+		{Inf(1), Float128{}, Float128{}},
+		{NaN(), Float128{}, Float128{}},
+	}
+
+	for _, tt := range tests {
+		got := SetPayload(tt.input)
+		if got != tt.quiet {
+			t.Errorf("SetPayload(%s) = %s, want %s", dump(tt.input), dump(got), dump(tt.quiet))
+		}
+		got = SetPayloadSignaling(tt.input)
+		if got != tt.signaling {
+			t.Errorf("SetPayloadSignaling(%s) = %s, want %s", dump(tt.input), dump(got), dump(tt.signaling))
+		}
+	}
+}
+
+func TestPayloadRoundTrip(t *testing.T) {
+	r := newXoshiro256pp()
+	for i := 0; i < 1000; i++ {
+		payload := int128.Uint128{H: r.Uint64() & payloadMask128H, L: r.Uint64()}
+		f := QuietNaN(payload)
+		if got := SetPayload(f.GetPayload()); got != f {
+			t.Errorf("SetPayload(%s.GetPayload()) = %s", dump(f), dump(got))
+		}
+		if payload.H|payload.L == 0 {
+			continue
+		}
+		f = SignalingNaN(payload)
+		if got := SetPayloadSignaling(f.GetPayload()); got != f {
+			t.Errorf("SetPayloadSignaling(%s.GetPayload()) = %s", dump(f), dump(got))
+		}
+	}
+}
+
+func TestNaNPropagation(t *testing.T) {
+	qNaN := Float128{0xffff_8000_0000_0000, 42}    // -NaN with payload 42
+	sNaN := Float128{0x7fff_0000_0000_0000, 43}    // sNaN with payload 43
+	quieted := Float128{0x7fff_8000_0000_0000, 43} // sNaN quieted
+	one := Float128{0x3fff_0000_0000_0000, 0}
+	zero := Float128{}
+
+	tests := []struct {
+		name  string
+		f     func() (Float128, Flags)
+		fast  func() Float128
+		want  Float128
+		flags Flags
+	}{
+		{
+			"Add(qNaN, 1)",
+			func() (Float128, Flags) { return qNaN.AddFlags(one, ToNearestEven) },
+			func() Float128 { return qNaN.Add(one) },
+			qNaN, 0,
+		},
+		{
+			"Add(1, sNaN)",
+			func() (Float128, Flags) { return one.AddFlags(sNaN, ToNearestEven) },
+			func() Float128 { return one.Add(sNaN) },
+			quieted, FlagInvalid,
+		},
+		{
+			"Add(qNaN, sNaN)",
+			func() (Float128, Flags) { return qNaN.AddFlags(sNaN, ToNearestEven) },
+			func() Float128 { return qNaN.Add(sNaN) },
+			qNaN, FlagInvalid,
+		},
+		{
+			"Sub(1, qNaN)",
+			func() (Float128, Flags) { return one.SubFlags(qNaN, ToNearestEven) },
+			func() Float128 { return one.Sub(qNaN) },
+			qNaN, 0,
+		},
+		{
+			"Mul(sNaN, 0)",
+			func() (Float128, Flags) { return sNaN.MulFlags(zero, ToNearestEven) },
+			func() Float128 { return sNaN.Mul(zero) },
+			quieted, FlagInvalid,
+		},
+		{
+			"Quo(0, sNaN)",
+			func() (Float128, Flags) { return zero.QuoFlags(sNaN, ToNearestEven) },
+			func() Float128 { return zero.Quo(sNaN) },
+			quieted, FlagInvalid,
+		},
+		{
+			"FMA(1, 1, sNaN)",
+			func() (Float128, Flags) { return FMAFlags(one, one, sNaN, ToNearestEven) },
+			func() Float128 { return FMA(one, one, sNaN) },
+			quieted, FlagInvalid,
+		},
+		{
+			"FMA(qNaN, 1, sNaN)",
+			func() (Float128, Flags) { return FMAFlags(qNaN, one, sNaN, ToNearestEven) },
+			func() Float128 { return FMA(qNaN, one, sNaN) },
+			qNaN, FlagInvalid,
+		},
+		{
+			"FMA(Inf, 0, qNaN)",
+			func() (Float128, Flags) { return FMAFlags(Inf(1), zero, qNaN, ToNearestEven) },
+			func() Float128 { return FMA(Inf(1), zero, qNaN) },
+			qNaN, FlagInvalid,
+		},
+		{
+			"Sqrt(sNaN)",
+			func() (Float128, Flags) { return sNaN.SqrtFlags(ToNearestEven) },
+			func() Float128 { return sNaN.Sqrt() },
+			quieted, FlagInvalid,
+		},
+		{
+			"Sqrt(qNaN)",
+			func() (Float128, Flags) { return qNaN.SqrtFlags(ToNearestEven) },
+			func() Float128 { return qNaN.Sqrt() },
+			qNaN, 0,
+		},
+	}
+
+	for _, tt := range tests {
+		got, flags := tt.f()
+		if got != tt.want || flags != tt.flags {
+			t.Errorf("%s: got %s, %s, want %s, %s", tt.name, dump(got), flags, dump(tt.want), tt.flags)
+		}
+		if got := tt.fast(); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, dump(got), dump(tt.want))
+		}
+	}
+}
+
+func TestNaNPropagation_Float64(t *testing.T) {
+	// the payload is preserved by the round trip through float64, if it fits in float64.
+	sNaN := Float128{0xffff_0000_0000_0000, 0x1000_0000_0000_0000}
+	f, flags := sNaN.Float64Flags(ToNearestEven)
+	if bits := math.Float64bits(f); bits != 0xfff8_0000_0000_0001 || flags != FlagInvalid {
+		t.Errorf("Float64Flags(%s) = %x, %s, want fff8000000000001, invalid", dump(sNaN), bits, flags)
+	}
+
+	got, flags := FromFloat64Flags(math.Float64frombits(0xfff0_0000_0000_0001))
+	want := Float128{0xffff_8000_0000_0000, 0x1000_0000_0000_0000}
+	if got != want || flags != FlagInvalid {
+		t.Errorf("FromFloat64Flags(sNaN) = %s, %s, want %s, invalid", dump(got), flags, dump(want))
+	}
+	if got := FromFloat64(math.Float64frombits(0xfff0_0000_0000_0001)); got != want {
+		t.Errorf("FromFloat64(sNaN) = %s, want %s", dump(got), dump(want))
+	}
+}
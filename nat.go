@@ -0,0 +1,214 @@
+package float128
+
+import (
+	"math/bits"
+
+	"github.com/shogo82148/int128"
+)
+
+// natWords is the number of words of nat.
+// It is large enough to hold the scaled values in the binary-decimal conversion:
+// 5^4966 × 2^116 for the smallest subnormal numbers,
+// and 5^4933 × 2^4 × 10 for the largest finite numbers.
+const natWords = 186
+
+// nat is an unsigned fixed-size multi-precision integer.
+// It is used for the exact conversion between binary and decimal numbers
+// without heap allocations.
+type nat struct {
+	w [natWords]uint64 // little-endian words
+	n int              // the number of words in use, w[n-1] != 0 if n > 0
+}
+
+func (z *nat) norm() {
+	for z.n > 0 && z.w[z.n-1] == 0 {
+		z.n--
+	}
+}
+
+func (z *nat) setUint64(x uint64) {
+	z.w[0] = x
+	z.n = 1
+	z.norm()
+}
+
+func (z *nat) setUint128(x int128.Uint128) {
+	z.w[0], z.w[1] = x.L, x.H
+	z.n = 2
+	z.norm()
+}
+
+func (z *nat) isZero() bool {
+	return z.n == 0
+}
+
+// cmp compares x and y and returns -1, 0, or +1.
+func (x *nat) cmp(y *nat) int {
+	if x.n != y.n {
+		if x.n < y.n {
+			return -1
+		}
+		return 1
+	}
+	for i := x.n - 1; i >= 0; i-- {
+		if x.w[i] != y.w[i] {
+			if x.w[i] < y.w[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// add sets z = x + y.
+func (z *nat) add(x, y *nat) {
+	if x.n < y.n {
+		x, y = y, x
+	}
+	var carry uint64
+	for i := 0; i < y.n; i++ {
+		z.w[i], carry = bits.Add64(x.w[i], y.w[i], carry)
+	}
+	for i := y.n; i < x.n; i++ {
+		z.w[i], carry = bits.Add64(x.w[i], 0, carry)
+	}
+	z.n = x.n
+	if carry != 0 {
+		z.w[z.n] = carry
+		z.n++
+	}
+}
+
+// sub sets z = z - x. z must be greater than or equal to x.
+func (z *nat) sub(x *nat) {
+	var borrow uint64
+	for i := 0; i < x.n; i++ {
+		z.w[i], borrow = bits.Sub64(z.w[i], x.w[i], borrow)
+	}
+	for i := x.n; borrow != 0; i++ {
+		z.w[i], borrow = bits.Sub64(z.w[i], 0, borrow)
+	}
+	z.norm()
+}
+
+// mulWord sets z = z × y.
+func (z *nat) mulWord(y uint64) {
+	var carry uint64
+	for i := 0; i < z.n; i++ {
+		hi, lo := bits.Mul64(z.w[i], y)
+		var c uint64
+		z.w[i], c = bits.Add64(lo, carry, 0)
+		carry = hi + c
+	}
+	if carry != 0 {
+		z.w[z.n] = carry
+		z.n++
+	}
+	if y == 0 {
+		z.n = 0
+	}
+}
+
+// setMul sets z = x × y.
+func (z *nat) setMul(x *nat, y int128.Uint128) {
+	z.n = x.n + 2
+	for i := 0; i < z.n; i++ {
+		z.w[i] = 0
+	}
+	for j, yj := range [2]uint64{y.L, y.H} {
+		var carry uint64
+		for i := 0; i < x.n; i++ {
+			hi, lo := bits.Mul64(x.w[i], yj)
+			var c uint64
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			z.w[i+j], c = bits.Add64(z.w[i+j], lo, 0)
+			carry = hi + c
+		}
+		z.w[x.n+j] = carry
+	}
+	z.norm()
+}
+
+// mulPow5 sets z = z × 5^k.
+func (z *nat) mulPow5(k int) {
+	const pow5_27 = 7450580596923828125 // 5^27, the largest power of 5 that fits in uint64
+	for ; k >= 27; k -= 27 {
+		z.mulWord(pow5_27)
+	}
+	p := uint64(1)
+	for ; k > 0; k-- {
+		p *= 5
+	}
+	z.mulWord(p)
+}
+
+// lsh sets z = z << s.
+func (z *nat) lsh(s uint) {
+	if z.n == 0 {
+		return
+	}
+	ws, bs := int(s/64), s%64
+	if bs == 0 {
+		for i := z.n - 1; i >= 0; i-- {
+			z.w[i+ws] = z.w[i]
+		}
+	} else {
+		z.w[z.n+ws] = z.w[z.n-1] >> (64 - bs)
+		for i := z.n - 1; i > 0; i-- {
+			z.w[i+ws] = z.w[i]<<bs | z.w[i-1]>>(64-bs)
+		}
+		z.w[ws] = z.w[0] << bs
+		z.n++
+	}
+	for i := 0; i < ws; i++ {
+		z.w[i] = 0
+	}
+	z.n += ws
+	z.norm()
+}
+
+// subMulWord sets z = z - x × y. z must be greater than or equal to x × y.
+func (z *nat) subMulWord(x *nat, y uint64) {
+	var carry, borrow uint64
+	for i := 0; i < x.n; i++ {
+		hi, lo := bits.Mul64(x.w[i], y)
+		var c uint64
+		lo, c = bits.Add64(lo, carry, 0)
+		carry = hi + c
+		z.w[i], borrow = bits.Sub64(z.w[i], lo, borrow)
+	}
+	for i := x.n; carry|borrow != 0; i++ {
+		z.w[i], borrow = bits.Sub64(z.w[i], carry, borrow)
+		carry = 0
+	}
+	z.norm()
+}
+
+// normShift returns the shift count that normalizes x,
+// so that the most significant word of x << normShift(x) is in [2^59, 2^60).
+// The normalized x can be multiplied by 10 without increasing the number of words.
+func (x *nat) normShift() uint {
+	return uint(bits.LeadingZeros64(x.w[x.n-1])+60) % 64
+}
+
+// divDigit sets z = z mod y and returns z / y.
+// y must be normalized by normShift, and z / y must be less than 10.
+func (z *nat) divDigit(y *nat) byte {
+	if z.n < y.n {
+		return 0
+	}
+
+	// estimate the quotient from the most significant words.
+	// the estimation is equal to the quotient or slightly less than it.
+	q := z.w[y.n-1] / (y.w[y.n-1] + 1)
+	if q > 0 {
+		z.subMulWord(y, q)
+	}
+	for z.cmp(y) >= 0 {
+		z.sub(y)
+		q++
+	}
+	return byte(q)
+}
@@ -0,0 +1,135 @@
+package float128
+
+import (
+	"math"
+
+	"github.com/shogo82148/int128"
+)
+
+var (
+	maxInt128 = int128.Int128{H: math.MaxInt64, L: math.MaxUint64}
+	minInt128 = int128.Int128{H: math.MinInt64, L: 0}
+)
+
+// NextUp returns the least floating-point number that compares greater than f, as nextUp of IEEE 754.
+//
+// Special cases are:
+//
+//	NextUp(±0) = the smallest positive subnormal number
+//	NextUp(the smallest negative subnormal number) = -0
+//	NextUp(the largest finite number) = +Inf
+//	NextUp(+Inf) = +Inf
+//	NextUp(-Inf) = -(the largest finite number)
+//	NextUp(NaN) = NaN
+func (f Float128) NextUp() Float128 {
+	switch {
+	case f.IsNaN():
+		return propagateNaN(f, f)
+	case f == inf:
+		return f
+	}
+	return fromComparable(f.comparable().Add(int128.Int128{L: 1}), f.h&signMask128H)
+}
+
+// NextDown returns the greatest floating-point number that compares less than f, as nextDown of IEEE 754.
+// It is the same as -(-f).NextUp().
+//
+// Special cases are:
+//
+//	NextDown(±0) = the smallest negative subnormal number
+//	NextDown(the smallest positive subnormal number) = +0
+//	NextDown(-(the largest finite number)) = -Inf
+//	NextDown(-Inf) = -Inf
+//	NextDown(+Inf) = the largest finite number
+//	NextDown(NaN) = NaN
+func (f Float128) NextDown() Float128 {
+	return f.Neg().NextUp().Neg()
+}
+
+// Nextafter returns the next representable Float128 value after x towards y.
+//
+// Special cases are the same as [math.Nextafter]:
+//
+//	Nextafter(x, x)   = x
+//	Nextafter(NaN, y) = NaN
+//	Nextafter(x, NaN) = NaN
+func Nextafter(x, y Float128) Float128 {
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return propagateNaN(x, y)
+	case x.Eq(y):
+		return x
+	case x.Lt(y):
+		return x.NextUp()
+	default:
+		return x.NextDown()
+	}
+}
+
+// Ulp returns the unit in the last place of f,
+// that is the distance between |f| and the next larger floating-point number in magnitude.
+// The result of the largest finite number is the distance to the hypothetical number beyond it.
+//
+// Special cases are:
+//
+//	Ulp(±0) = the smallest positive subnormal number
+//	Ulp(±Inf) = +Inf
+//	Ulp(NaN) = NaN
+func (f Float128) Ulp() Float128 {
+	switch {
+	case f.IsNaN():
+		return propagateNaN(f, f)
+	case f.IsInf(0):
+		return inf
+	}
+
+	// the subnormal numbers have the same ulp as the smallest normal numbers.
+	exp := max((f.h>>(shift128-64))&mask128, 1)
+	if exp > shift128 {
+		// the ulp is a normal number 2^(exp-bias128-shift128).
+		return Float128{(exp - shift128) << (shift128 - 64), 0}
+	}
+
+	// the ulp is a subnormal number.
+	u := one.Lsh(uint(exp - 1))
+	return Float128{u.H, u.L}
+}
+
+// UlpDistance returns the signed number of steps of [Float128.NextUp] from a to b.
+// It is positive if a < b, negative if a > b, and zero if a == b.
+// -0 and +0 are the same point, and infinities are the next steps beyond the largest finite numbers.
+//
+// The result saturates to the maximum or minimum value of int128.Int128 if it overflows,
+// which happens if a and b have opposite signs and both are large in magnitude,
+// e.g. from -(the largest finite number) to the largest finite number.
+// If a or b is NaN, UlpDistance returns the maximum value of int128.Int128.
+func UlpDistance(a, b Float128) int128.Int128 {
+	if a.IsNaN() || b.IsNaN() {
+		return maxInt128
+	}
+
+	ia, ib := a.comparable(), b.comparable()
+	d := ib.Sub(ia)
+
+	// the subtraction overflows only if the signs of the operands differ and the sign of the result is wrong.
+	if (ia.H < 0) != (ib.H < 0) && (d.H < 0) != (ib.H < 0) {
+		if ib.H < 0 {
+			return minInt128
+		}
+		return maxInt128
+	}
+	return d
+}
+
+// fromComparable is the inverse of [Float128.comparable].
+// comparable maps both -0 and +0 to zero, and sign is the sign of the zero.
+func fromComparable(i int128.Int128, sign uint64) Float128 {
+	if i.H < 0 {
+		i = i.Neg()
+		return Float128{uint64(i.H) | signMask128H, i.L}
+	}
+	if i.H|int64(i.L) == 0 {
+		return Float128{sign, 0}
+	}
+	return Float128{uint64(i.H), i.L}
+}
@@ -0,0 +1,257 @@
+package float128
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shogo82148/int128"
+)
+
+func TestNextUp(t *testing.T) {
+	tests := []struct {
+		input Float128
+		up    Float128
+	}{
+		// 1 and 1 + 2^-112
+		{Float128{0x3fff_0000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 1}},
+		// 1 - 2^-113 and 1
+		{Float128{0x3ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, Float128{0x3fff_0000_0000_0000, 0}},
+		// -1 and -(1 - 2^-113)
+		{Float128{0xbfff_0000_0000_0000, 0}, Float128{0xbffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}},
+		// carry from the low bits to the high bits
+		{Float128{0x3fff_0000_0000_0000, 0xffff_ffff_ffff_ffff}, Float128{0x3fff_0000_0000_0001, 0}},
+
+		// across zero
+		{Float128{}, Float128{0, 1}},
+		{Float128{0x8000_0000_0000_0000, 0}, Float128{0, 1}},
+		{Float128{0x8000_0000_0000_0000, 1}, Float128{0x8000_0000_0000_0000, 0}},
+		{Float128{0x8000_0000_0000_0000, 2}, Float128{0x8000_0000_0000_0000, 1}},
+
+		// across the subnormal boundary
+		{Float128{0x0000_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, Float128{0x0001_0000_0000_0000, 0}},
+		{Float128{0x8001_0000_0000_0000, 0}, Float128{0x8000_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}},
+
+		// the largest finite number and infinities
+		{Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, Inf(1)},
+		{Inf(1), Inf(1)},
+		{Inf(-1), Float128{0xfffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.input.NextUp(); got != tt.up {
+			t.Errorf("%s.NextUp() = %s, want %s", dump(tt.input), dump(got), dump(tt.up))
+		}
+		if got := tt.up.NextDown(); tt.input != tt.up && !got.Eq(tt.input) {
+			t.Errorf("%s.NextDown() = %s, want %s", dump(tt.up), dump(got), dump(tt.input))
+		}
+	}
+
+	// NaN
+	if got := NaN().NextUp(); !got.IsNaN() {
+		t.Errorf("NaN.NextUp() = %s, want NaN", dump(got))
+	}
+	if got := NaN().NextDown(); !got.IsNaN() {
+		t.Errorf("NaN.NextDown() = %s, want NaN", dump(got))
+	}
+}
+
+func TestNextDown(t *testing.T) {
+	tests := []struct {
+		input Float128
+		down  Float128
+	}{
+		{Float128{}, Float128{0x8000_0000_0000_0000, 1}},
+		{Float128{0x8000_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 1}},
+		{Float128{0, 1}, Float128{}},
+		{Float128{0x3fff_0000_0000_0000, 0}, Float128{0x3ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}},
+		{Inf(1), Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}},
+		{Float128{0xfffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, Inf(-1)},
+		{Inf(-1), Inf(-1)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.input.NextDown(); got != tt.down {
+			t.Errorf("%s.NextDown() = %s, want %s", dump(tt.input), dump(got), dump(tt.down))
+		}
+	}
+}
+
+func TestNextafter(t *testing.T) {
+	tests := []struct {
+		x, y Float128
+		want Float128
+	}{
+		{Float128{0x3fff_0000_0000_0000, 0}, Inf(1), Float128{0x3fff_0000_0000_0000, 1}},
+		{Float128{0x3fff_0000_0000_0000, 0}, Inf(-1), Float128{0x3ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}},
+		{Float128{}, Float128{0xbfff_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 1}},
+		{Inf(1), Float128{}, Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}},
+
+		// Nextafter(x, x) = x
+		{Float128{0x3fff_0000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 0}},
+		{Float128{}, Float128{0x8000_0000_0000_0000, 0}, Float128{}},
+		{Float128{0x8000_0000_0000_0000, 0}, Float128{}, Float128{0x8000_0000_0000_0000, 0}},
+		{Inf(1), Inf(1), Inf(1)},
+	}
+
+	for _, tt := range tests {
+		if got := Nextafter(tt.x, tt.y); got != tt.want {
+			t.Errorf("Nextafter(%s, %s) = %s, want %s", dump(tt.x), dump(tt.y), dump(got), dump(tt.want))
+		}
+	}
+
+	// NaN
+	if got := Nextafter(NaN(), Float128{}); !got.IsNaN() {
+		t.Errorf("Nextafter(NaN, 0) = %s, want NaN", dump(got))
+	}
+	if got := Nextafter(Float128{}, NaN()); !got.IsNaN() {
+		t.Errorf("Nextafter(0, NaN) = %s, want NaN", dump(got))
+	}
+}
+
+func TestUlp(t *testing.T) {
+	tests := []struct {
+		input Float128
+		ulp   Float128
+	}{
+		// 1 and 2^-112
+		{Float128{0x3fff_0000_0000_0000, 0}, Float128{0x3f8f_0000_0000_0000, 0}},
+		{Float128{0xbfff_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, Float128{0x3f8f_0000_0000_0000, 0}},
+		// the largest finite number
+		{Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, Float128{0x7f8e_0000_0000_0000, 0}},
+		// the smallest normal number whose ulp is normal
+		{Float128{0x0071_0000_0000_0000, 0}, Float128{0x0001_0000_0000_0000, 0}},
+		// the ulp is subnormal
+		{Float128{0x0070_0000_0000_0000, 0}, Float128{0x0000_8000_0000_0000, 0}},
+		{Float128{0x0002_0000_0000_0000, 0}, Float128{0, 2}},
+		// the smallest normal number and subnormal numbers
+		{Float128{0x0001_0000_0000_0000, 0}, Float128{0, 1}},
+		{Float128{0x8000_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, Float128{0, 1}},
+		{Float128{0, 1}, Float128{0, 1}},
+
+		// special cases
+		{Float128{}, Float128{0, 1}},
+		{Float128{0x8000_0000_0000_0000, 0}, Float128{0, 1}},
+		{Inf(1), Inf(1)},
+		{Inf(-1), Inf(1)},
+	}
+
+	for _, tt := range tests {
+		if got := tt.input.Ulp(); got != tt.ulp {
+			t.Errorf("%s.Ulp() = %s, want %s", dump(tt.input), dump(got), dump(tt.ulp))
+		}
+	}
+
+	if got := NaN().Ulp(); !got.IsNaN() {
+		t.Errorf("NaN.Ulp() = %s, want NaN", dump(got))
+	}
+}
+
+func TestUlp_Random(t *testing.T) {
+	// the ulp is the distance to the next larger number in magnitude.
+	r := newXoshiro256pp()
+	for i := 0; i < 100000; i++ {
+		x, _ := r.Float128Pair()
+		x = x.Abs()
+		if x.IsNaN() || x.IsInf(0) || x == (Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}) {
+			continue
+		}
+		if got, want := x.Ulp(), x.NextUp().Sub(x); got != want {
+			t.Errorf("%s.Ulp() = %s, want %s", dump(x), dump(got), dump(want))
+		}
+	}
+}
+
+func TestUlpDistance(t *testing.T) {
+	maxFinite := Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}
+	tests := []struct {
+		a, b Float128
+		want int128.Int128
+	}{
+		{Float128{0x3fff_0000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 0}, int128.Int128{}},
+		{Float128{0x3fff_0000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 5}, int128.Int128{L: 5}},
+		{Float128{0x3fff_0000_0000_0000, 5}, Float128{0x3fff_0000_0000_0000, 0}, int128.Int128{L: 5}.Neg()},
+		// 1 - 2^-113 and 1
+		{Float128{0x3ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, Float128{0x3fff_0000_0000_0000, 0}, int128.Int128{L: 1}},
+
+		// across zero
+		{Float128{}, Float128{0x8000_0000_0000_0000, 0}, int128.Int128{}},
+		{Float128{0x8000_0000_0000_0000, 3}, Float128{0, 2}, int128.Int128{L: 5}},
+		{Float128{0, 2}, Float128{0x8000_0000_0000_0000, 3}, int128.Int128{L: 5}.Neg()},
+
+		// infinities
+		{maxFinite, Inf(1), int128.Int128{L: 1}},
+		{Float128{}, Inf(1), int128.Int128{H: 0x7fff_0000_0000_0000, L: 0}},
+		{Inf(-1), Float128{}, int128.Int128{H: 0x7fff_0000_0000_0000, L: 0}},
+		{Float128{0x8000_0000_0000_0000, 1}, Inf(1), int128.Int128{H: 0x7fff_0000_0000_0000, L: 1}},
+
+		// saturation
+		{maxFinite.Neg(), maxFinite, int128.Int128{H: math.MaxInt64, L: math.MaxUint64}},
+		{Inf(-1), Inf(1), int128.Int128{H: math.MaxInt64, L: math.MaxUint64}},
+		{Inf(1), Inf(-1), int128.Int128{H: math.MinInt64}},
+
+		// NaN
+		{NaN(), Float128{}, int128.Int128{H: math.MaxInt64, L: math.MaxUint64}},
+		{Float128{}, NaN(), int128.Int128{H: math.MaxInt64, L: math.MaxUint64}},
+	}
+
+	for _, tt := range tests {
+		if got := UlpDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("UlpDistance(%s, %s) = %d, want %d", dump(tt.a), dump(tt.b), got, tt.want)
+		}
+	}
+}
+
+func TestUlpDistance_Random(t *testing.T) {
+	// the distance is consistent with Compare and NextUp.
+	r := newXoshiro256pp()
+	for i := 0; i < 100000; i++ {
+		a, b := r.Float128Pair()
+		if a.IsNaN() || b.IsNaN() {
+			continue
+		}
+		d := UlpDistance(a, b)
+		if d.Cmp(int128.Int128{}) != b.Compare(a) {
+			t.Errorf("UlpDistance(%s, %s) = %d, but %s.Compare(%s) = %d", dump(a), dump(b), d, dump(b), dump(a), b.Compare(a))
+		}
+		if a.IsInf(1) || d == maxInt128 || d == minInt128 {
+			// saturated
+			continue
+		}
+		if got, want := UlpDistance(a.NextUp(), b), d.Sub(int128.Int128{L: 1}); got != want {
+			t.Errorf("UlpDistance(%s, %s) = %d, want %d", dump(a.NextUp()), dump(b), got, want)
+		}
+	}
+}
+
+func TestNextUp_Float64(t *testing.T) {
+	// the results are consistent with the math package.
+	r := newXoshiro256pp()
+	for i := 0; i < 100000; i++ {
+		a := math.Float64frombits(r.Uint64())
+		b := math.Float64frombits(r.Uint64())
+		if math.IsNaN(a) || math.IsNaN(b) || a == b {
+			continue
+		}
+		x, y := FromFloat64(a), FromFloat64(b)
+
+		// the neighbor in Float128 rounds back to a in float64.
+		if got := Nextafter(x, y).Float64(); got != a {
+			t.Errorf("Nextafter(%x, %x) = %x, want %x", a, b, got, a)
+		}
+
+		// stepping to the neighbor in float64 takes 2^60 steps in Float128,
+		// if both are normal numbers with the same binary exponent.
+		next := math.Nextafter(a, b)
+		if math.Abs(a) < 0x1p-1022 || math.Abs(next) < 0x1p-1022 || math.IsInf(a, 0) || math.IsInf(next, 0) ||
+			math.Ilogb(a) != math.Ilogb(next) {
+			continue
+		}
+		want := int128.Int128{L: 1 << 60}
+		if a > b {
+			want = want.Neg()
+		}
+		if got := UlpDistance(x, FromFloat64(next)); got != want {
+			t.Errorf("UlpDistance(%x, %x) = %d, want %d", a, next, got, want)
+		}
+	}
+}
@@ -0,0 +1,569 @@
+package float128
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/shogo82148/int128"
+)
+
+// pow10tab[i] is 10^i.
+// 10^i is exactly representable in Float128 for i <= 48, because 5^48 < 2^113.
+var pow10tab = func() (t [49]Float128) {
+	t[0] = Float128{0x3fff_0000_0000_0000, 0} // 1
+	ten := Float128{0x4002_4000_0000_0000, 0}
+	for i := 1; i < len(t); i++ {
+		t[i] = t[i-1].Mul(ten)
+	}
+	return
+}()
+
+// Parse converts the string s to a floating-point number,
+// rounding to nearest, ties to even.
+//
+// Parse accepts decimal and hexadecimal floating-point numbers as defined by
+// the Go syntax for floating-point literals, including underscores between digits.
+// Hexadecimal floating-point numbers must have a binary exponent, e.g. "0x1.8p+1",
+// which is the format of [Float128.GoString] and "%Qa" of libquadmath.
+// It also recognizes the strings "NaN", and the (possibly signed) strings "Inf" and "Infinity"
+// as their respective special floating point values. It ignores case when matching.
+//
+// The errors that Parse returns have concrete type [*strconv.NumError]
+// and include err.Num = s.
+// If s is not syntactically well-formed, Parse returns err.Err = [strconv.ErrSyntax].
+// If s is syntactically well-formed but is more than 1/2 ULP
+// away from the largest floating point number,
+// Parse returns f = ±Inf, err.Err = [strconv.ErrRange].
+func Parse(s string) (Float128, error) {
+	if f, n, ok := special(s); ok {
+		if n != len(s) {
+			return Float128{}, syntaxError(s)
+		}
+		return f, nil
+	}
+
+	if hasHexPrefix(s) {
+		f, n, ok := readHexFloat(s)
+		if !ok || n != len(s) {
+			return Float128{}, syntaxError(s)
+		}
+		if f.IsInf(0) {
+			return f, rangeError(s)
+		}
+		return f, nil
+	}
+
+	var buf [64]byte
+	neg, digits, dp, n, ok := readFloat(s, buf[:0])
+	if !ok || n != len(s) {
+		return Float128{}, syntaxError(s)
+	}
+
+	var sign uint64
+	if neg {
+		sign = signMask128H
+	}
+	f, ok := decimalToFloat128(sign, digits, dp)
+	if !ok {
+		return f, rangeError(s)
+	}
+	return f, nil
+}
+
+// Scan implements [fmt.Scanner].
+// It accepts the verbs 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X' and 'v',
+// and reads a number in the syntax accepted by [Parse].
+func (f *Float128) Scan(state fmt.ScanState, verb rune) error {
+	switch verb {
+	case 'b', 'e', 'E', 'f', 'F', 'g', 'G', 'x', 'X', 'v':
+	default:
+		return fmt.Errorf("float128: bad verb '%%%c' for Float128", verb)
+	}
+
+	state.SkipSpace()
+	v, err := Parse(floatToken(state))
+	if err != nil {
+		return err
+	}
+	*f = v
+	return nil
+}
+
+// floatToken reads the longest prefix of the input that looks like a floating-point literal
+// in the syntax accepted by [Parse], as fmt does for float64.
+// The rest of the input, e.g. "x" of "1.5x", is left unread.
+func floatToken(state fmt.ScanState) string {
+	s := &floatScanner{state: state}
+
+	// NaN?
+	if s.accept("nN") && s.accept("aA") && s.accept("nN") {
+		return string(s.buf)
+	}
+
+	// leading sign?
+	s.accept("+-")
+
+	// Inf or Infinity?
+	if s.accept("iI") && s.accept("nN") && s.accept("fF") {
+		if s.accept("iI") && s.accept("nN") && s.accept("iI") && s.accept("tT") {
+			s.accept("yY")
+		}
+		return string(s.buf)
+	}
+
+	digits := "0123456789_"
+	exp := "eE"
+	if s.accept("0") && s.accept("xX") {
+		digits = "0123456789aAbBcCdDeEfF_"
+		exp = "pP"
+	}
+
+	// digits?
+	for s.accept(digits) {
+	}
+	// decimal point?
+	if s.accept(".") {
+		// fraction?
+		for s.accept(digits) {
+		}
+	}
+	// exponent?
+	if s.accept(exp) {
+		// leading sign?
+		s.accept("+-")
+		// digits?
+		for s.accept("0123456789_") {
+		}
+	}
+	return string(s.buf)
+}
+
+type floatScanner struct {
+	state fmt.ScanState
+	buf   []byte
+}
+
+// accept reads the next rune if it is in ok, and reports whether it is read.
+func (s *floatScanner) accept(ok string) bool {
+	r, _, err := s.state.ReadRune()
+	if err != nil {
+		return false
+	}
+	if !strings.ContainsRune(ok, r) {
+		s.state.UnreadRune()
+		return false
+	}
+	s.buf = append(s.buf, byte(r)) // ok contains only ASCII characters.
+	return true
+}
+
+func syntaxError(s string) *strconv.NumError {
+	return &strconv.NumError{Func: "ParseFloat128", Num: s, Err: strconv.ErrSyntax}
+}
+
+func rangeError(s string) *strconv.NumError {
+	return &strconv.NumError{Func: "ParseFloat128", Num: s, Err: strconv.ErrRange}
+}
+
+// special returns the floating-point value for the special,
+// possibly signed floating-point representations inf, infinity,
+// and NaN. The result is ok if a prefix of s contains one
+// of these representations and n is the length of that prefix.
+// The character case is ignored.
+func special(s string) (f Float128, n int, ok bool) {
+	if len(s) == 0 {
+		return
+	}
+	sign := 1
+	nsign := 0
+	switch s[0] {
+	case '+', '-':
+		if s[0] == '-' {
+			sign = -1
+		}
+		nsign = 1
+		s = s[1:]
+		fallthrough
+	case 'i', 'I':
+		n := commonPrefixLenIgnoreCase(s, "infinity")
+		// Anything longer than "inf" is ok, but if we
+		// don't have "infinity", only consume "inf".
+		if 3 < n && n < 8 {
+			n = 3
+		}
+		if n == 3 || n == 8 {
+			return Inf(sign), nsign + n, true
+		}
+	case 'n', 'N':
+		if commonPrefixLenIgnoreCase(s, "nan") == 3 {
+			return nan, 3, true
+		}
+	}
+	return
+}
+
+// commonPrefixLenIgnoreCase returns the length of the common
+// prefix of s and prefix, with the character case of s ignored.
+// The prefix argument must be all lower-case.
+func commonPrefixLenIgnoreCase(s, prefix string) int {
+	n := len(prefix)
+	if n > len(s) {
+		n = len(s)
+	}
+	for i := 0; i < n; i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		if c != prefix[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// readFloat reads a decimal floating-point number from the prefix of s.
+// The significant digits are appended to buf[:0] without leading and trailing zeros,
+// and the value is 0.digits × 10^dp.
+// n is the length of the prefix.
+func readFloat(s string, buf []byte) (neg bool, digits []byte, dp int, n int, ok bool) {
+	underscores := false
+	digits = buf[:0]
+	i := 0
+
+	// optional sign
+	if i >= len(s) {
+		return
+	}
+	switch {
+	case s[i] == '+':
+		i++
+	case s[i] == '-':
+		neg = true
+		i++
+	}
+
+	// digits
+	sawdot := false
+	sawdigits := false
+	nd := 0 // the number of digits including trailing zeros
+loop:
+	for ; i < len(s); i++ {
+		switch c := s[i]; true {
+		case c == '_':
+			underscores = true
+			continue
+		case c == '.':
+			if sawdot {
+				break loop
+			}
+			sawdot = true
+			dp = nd
+			continue
+		case '0' <= c && c <= '9':
+			sawdigits = true
+			if c == '0' && nd == 0 { // ignore leading zeros
+				dp--
+				continue
+			}
+			nd++
+			digits = append(digits, c)
+			continue
+		}
+		break
+	}
+	if !sawdigits {
+		return
+	}
+	if !sawdot {
+		dp = nd
+	}
+
+	// optional exponent moves decimal point.
+	// if we read a very large, very long number,
+	// just be sure to move the decimal point by
+	// a lot (say, 100000).  it doesn't matter if it's
+	// not the exact number.
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i >= len(s) {
+			return
+		}
+		esign := 1
+		if s[i] == '+' {
+			i++
+		} else if s[i] == '-' {
+			i++
+			esign = -1
+		}
+		if i >= len(s) || s[i] < '0' || s[i] > '9' {
+			return
+		}
+		e := 0
+		for ; i < len(s) && ('0' <= s[i] && s[i] <= '9' || s[i] == '_'); i++ {
+			if s[i] == '_' {
+				underscores = true
+				continue
+			}
+			if e < 100000 {
+				e = e*10 + int(s[i]) - '0'
+			}
+		}
+		dp += e * esign
+	}
+
+	if underscores && !underscoreOK(s[:i]) {
+		return
+	}
+
+	// trim trailing zeros
+	for len(digits) > 0 && digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+	}
+
+	n = i
+	ok = true
+	return
+}
+
+// hasHexPrefix reports whether s starts with the (possibly signed) prefix "0x" or "0X".
+func hasHexPrefix(s string) bool {
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	return len(s) >= 2 && s[0] == '0' && lower(s[1]) == 'x'
+}
+
+// readHexFloat reads a hexadecimal floating-point number from the prefix of s,
+// and returns the floating point number nearest to it.
+// s must start with the (possibly signed) prefix "0x".
+// n is the length of the prefix.
+func readHexFloat(s string) (f Float128, n int, ok bool) {
+	underscores := false
+	i := 0
+
+	// optional sign
+	var sign uint64
+	switch s[i] {
+	case '+':
+		i++
+	case '-':
+		sign = signMask128H
+		i++
+	}
+
+	// base prefix
+	i += 2
+
+	// digits
+	var mant int128.Uint128
+	var sticky uint64
+	exp := 0 // the value is mant × 2^exp
+	sawdot := false
+	sawdigits := false
+loop:
+	for ; i < len(s); i++ {
+		var d uint64
+		switch c := s[i]; true {
+		case c == '_':
+			underscores = true
+			continue
+		case c == '.':
+			if sawdot {
+				break loop
+			}
+			sawdot = true
+			continue
+		case '0' <= c && c <= '9':
+			d = uint64(c - '0')
+		case 'a' <= lower(c) && lower(c) <= 'f':
+			d = uint64(lower(c) - 'a' + 10)
+		default:
+			break loop
+		}
+		sawdigits = true
+		if mant.H>>60 == 0 {
+			mant = mant.Lsh(4).Or(int128.Uint128{L: d})
+			if sawdot {
+				exp -= 4
+			}
+		} else {
+			// mant is full; the rest of digits only affect the rounding.
+			sticky |= d
+			if !sawdot {
+				exp += 4
+			}
+		}
+	}
+	if !sawdigits {
+		return
+	}
+
+	// the binary exponent is mandatory.
+	if i >= len(s) || lower(s[i]) != 'p' {
+		return
+	}
+	i++
+	if i >= len(s) {
+		return
+	}
+	esign := 1
+	if s[i] == '+' {
+		i++
+	} else if s[i] == '-' {
+		i++
+		esign = -1
+	}
+	if i >= len(s) || s[i] < '0' || s[i] > '9' {
+		return
+	}
+	e := 0
+	for ; i < len(s) && ('0' <= s[i] && s[i] <= '9' || s[i] == '_'); i++ {
+		if s[i] == '_' {
+			underscores = true
+			continue
+		}
+		if e < 100000 {
+			e = e*10 + int(s[i]) - '0'
+		}
+	}
+	exp += e * esign
+
+	if underscores && !underscoreOK(s[:i]) {
+		return
+	}
+
+	f = pack(sign, clampExp(exp), mant, sticky)
+	n = i
+	ok = true
+	return
+}
+
+// clampExp converts the binary exponent exp to int32.
+// It clamps exp to ±2^20, which is far out of the range of Float128 anyway,
+// so that the rounding still overflows or underflows without overflowing int32.
+func clampExp(exp int) int32 {
+	if exp > 1<<20 {
+		return 1 << 20
+	} else if exp < -1<<20 {
+		return -1 << 20
+	}
+	return int32(exp)
+}
+
+// lower returns the lower-case of the ASCII letter c.
+func lower(c byte) byte {
+	return c | ('x' - 'X')
+}
+
+// underscoreOK reports whether the underscores in s are allowed.
+// Checking them in this one function lets all the parsers skip over them simply.
+// Underscore must appear only between digits or between a base prefix and a digit.
+func underscoreOK(s string) bool {
+	// saw tracks the last character (class) we saw:
+	// ^ for beginning of number,
+	// 0 for a digit or base prefix,
+	// _ for an underscore,
+	// ! for none of the above.
+	saw := '^'
+	i := 0
+
+	// Optional sign.
+	if len(s) >= 1 && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+
+	// Optional base prefix.
+	hex := false
+	if len(s) >= 2 && s[0] == '0' && lower(s[1]) == 'x' {
+		i = 2
+		saw = '0' // base prefix counts as a digit for "underscore as digit separator"
+		hex = true
+	}
+
+	// Number proper.
+	for ; i < len(s); i++ {
+		// Digits are always okay.
+		if '0' <= s[i] && s[i] <= '9' || hex && 'a' <= lower(s[i]) && lower(s[i]) <= 'f' {
+			saw = '0'
+			continue
+		}
+		// Underscore must follow digit.
+		if s[i] == '_' {
+			if saw != '0' {
+				return false
+			}
+			saw = '_'
+			continue
+		}
+		// Underscore must also be followed by digit.
+		if saw == '_' {
+			return false
+		}
+		// Saw non-digit, non-underscore.
+		saw = '!'
+	}
+	return saw != '_'
+}
+
+// decimalToFloat128 returns the floating point number nearest to (-1)^sign × 0.digits × 10^dp.
+// ok is false if the result overflows.
+func decimalToFloat128(sign uint64, digits []byte, dp int) (f Float128, ok bool) {
+	if len(digits) == 0 {
+		return Float128{sign, 0}, true
+	}
+
+	// 10^(dp-1) <= |f| < 10^dp
+	if dp > 4933 {
+		// |f| >= 10^4933, it is larger than the largest finite number (≈ 1.19e4932).
+		return Float128{sign | inf.h, inf.l}, false
+	}
+	if dp < -4966 {
+		// |f| < 10^-4967, it is less than half of the smallest subnormal number (≈ 6.48e-4966).
+		return Float128{sign, 0}, true
+	}
+
+	exp := dp - len(digits)
+	if len(digits) <= 19 && -len(pow10tab) < exp && exp < len(pow10tab) {
+		// fast path: both the mantissa and the power of 10 are exactly representable,
+		// so only one rounding occurs in the multiplication or the division.
+		var mant uint64
+		for _, c := range digits {
+			mant = mant*10 + uint64(c-'0')
+		}
+		f = pack(sign, 0, int128.Uint128{L: mant}, 0)
+		if exp >= 0 {
+			f = f.Mul(pow10tab[exp])
+		} else {
+			f = f.Quo(pow10tab[-exp])
+		}
+		return f, !f.IsInf(0)
+	}
+
+	// slow path: exact computation using big integers.
+	mant, _ := new(big.Int).SetString(string(digits), 10)
+	if exp >= 0 {
+		pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+		mant.Mul(mant, pow)
+		f, _ = bigIntToFloat128(sign, mant, 0, 0, big.ToNearestEven)
+		return f, !f.IsInf(0)
+	}
+
+	// compute the quotient mant / 10^-exp with at least 128 significant bits.
+	den := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil)
+	shift := 129 - (mant.BitLen() - den.BitLen())
+	if shift >= 0 {
+		mant.Lsh(mant, uint(shift))
+	} else {
+		den.Lsh(den, uint(-shift))
+	}
+	mant.QuoRem(mant, den, den)
+	var sticky uint64
+	if den.Sign() != 0 {
+		sticky = 1
+	}
+	f, _ = bigIntToFloat128(sign, mant, -int32(shift), sticky, big.ToNearestEven)
+	return f, !f.IsInf(0)
+}
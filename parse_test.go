@@ -0,0 +1,259 @@
+package float128
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Float128
+	}{
+		// special cases
+		{"0", Float128{0, 0}},
+		{"-0", Float128{signMask128H, 0}},
+		{"+0.000e+100", Float128{0, 0}},
+		{"inf", Inf(1)},
+		{"+Inf", Inf(1)},
+		{"-Infinity", Inf(-1)},
+		{"NaN", NaN()},
+		{"nan", NaN()},
+
+		// normal numbers
+		{"1", Float128{0x3fff_0000_0000_0000, 0}},
+		{"-2", Float128{0xc000_0000_0000_0000, 0}},
+		{"1.5", Float128{0x3fff_8000_0000_0000, 0}},
+		{"0.1", Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}},
+		{".1", Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}},
+		{"1e-1", Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}},
+		{"1e48", Float128{0x409e_5e53_1a0a_1c87, 0x2bad_2ce1_6256_fe82}},
+		{"1e-48", Float128{0x3f5f_7624_f8a7_62fd, 0x82b2_aac1_8030_b01b}},
+		{"1e-4000", Float128{0x0c17_387a_e70c_9e70, 0x0b80_4973_2d11_a23d}},
+		{"123456789012345678901234567890", Float128{0x405f_8ee9_0ff6_c373, 0xe0ee_4e3f_0ad2_0000}},
+		{"3.141592653589793238462643383279502884197", Float128{0x4000_921f_b544_42d1, 0x8469_898c_c517_01b8}},
+		{"3.141_592_653_589_793_238_462_643_383_279_502_884_197", Float128{0x4000_921f_b544_42d1, 0x8469_898c_c517_01b8}},
+
+		// the largest finite number
+		{"1.18973149535723176508575932662800702e4932", Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}},
+		{"1.189731495357231765085759326628007016e4932", Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}},
+
+		// the smallest positive normal number
+		{"3.3621031431120935062626778173217526e-4932", Float128{0x0001_0000_0000_0000, 0}},
+
+		// subnormal numbers
+		{"6.475175119438025110924438958227646552e-4966", Float128{0, 1}},
+		{"3.2375875597190125554622194791138232762e-4966", Float128{0, 0}}, // just below the half of the smallest subnormal number
+		{"3.2375875597190125554622194791138232763e-4966", Float128{0, 1}}, // just above the half of the smallest subnormal number
+		{"1e-5000", Float128{0, 0}},
+		{"-1e-5000", Float128{signMask128H, 0}},
+
+		// hexadecimal
+		{"0x1p0", Float128{0x3fff_0000_0000_0000, 0}},
+		{"0X1P-1", Float128{0x3ffe_0000_0000_0000, 0}},
+		{"-0x1.8p+1", Float128{0xc000_8000_0000_0000, 0}},
+		{"0x.8p1", Float128{0x3fff_0000_0000_0000, 0}},
+		{"0x10p-4", Float128{0x3fff_0000_0000_0000, 0}},
+		{"0x_1_0p-4", Float128{0x3fff_0000_0000_0000, 0}},
+		{"+0x1.999999999999999999999999999ap-4", Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}},
+		{"0x1.921fb54442d18469898cc51701b8p+1", Float128{0x4000_921f_b544_42d1, 0x8469_898c_c517_01b8}},
+		{"0x1.ffffffffffffffffffffffffffffp+16383", Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}},
+		{"0x0.0000000000000000000000000001p-16382", Float128{0, 1}},
+		{"0x1p-16494", Float128{0, 1}},
+		{"-0x0.0p+0", Float128{signMask128H, 0}},
+
+		// hexadecimal with more than 112 fraction bits
+		{"0x1.00000000000000000000000000008p+0", Float128{0x3fff_0000_0000_0000, 0}},           // tie, round to even
+		{"0x1.00000000000000000000000000018p+0", Float128{0x3fff_0000_0000_0000, 2}},           // tie, round to even
+		{"0x1.000000000000000000000000000080000000001p+0", Float128{0x3fff_0000_0000_0000, 1}}, // above the tie
+		{"0x1.00000000000000000000000000007ffffffffffp+0", Float128{0x3fff_0000_0000_0000, 0}}, // below the tie
+		{"0x1.ffffffffffffffffffffffffffff8p+0", Float128{0x4000_0000_0000_0000, 0}},           // carry into the exponent
+		{"0x0.00000000000000000000000000008p-16382", Float128{0, 0}},                           // half of the smallest subnormal
+		{"0x0.000000000000000000000000000081p-16382", Float128{0, 1}},                          // above the half of the smallest subnormal
+		{"0x0.ffffffffffffffffffffffffffff8p-16382", Float128{0x0001_0000_0000_0000, 0}},       // subnormal rounds up to normal
+		{"0x123456789abcdef0123456789abcdef0123456789p-160", Float128{0x3fff_2345_6789_abcd, 0xef01_2345_6789_abce}},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) returns error: %v", tt.input, err)
+			continue
+		}
+		if !equals(got, tt.want) {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, dump(got), dump(tt.want))
+		}
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Float128
+		err   error
+	}{
+		{"", Float128{}, strconv.ErrSyntax},
+		{"+", Float128{}, strconv.ErrSyntax},
+		{"1e", Float128{}, strconv.ErrSyntax},
+		{"1e+", Float128{}, strconv.ErrSyntax},
+		{"1.2.3", Float128{}, strconv.ErrSyntax},
+		{"1x", Float128{}, strconv.ErrSyntax},
+		{"infinit", Float128{}, strconv.ErrSyntax},
+		{"-nan", Float128{}, strconv.ErrSyntax},
+		{"_1", Float128{}, strconv.ErrSyntax},
+		{"1_", Float128{}, strconv.ErrSyntax},
+		{"1__0", Float128{}, strconv.ErrSyntax},
+		{"1_.0", Float128{}, strconv.ErrSyntax},
+		{"0x", Float128{}, strconv.ErrSyntax},
+		{"0x1", Float128{}, strconv.ErrSyntax},
+		{"0x1.8", Float128{}, strconv.ErrSyntax},
+		{"0x1p", Float128{}, strconv.ErrSyntax},
+		{"0xp1", Float128{}, strconv.ErrSyntax},
+		{"0x1g", Float128{}, strconv.ErrSyntax},
+		{"0x1_p0", Float128{}, strconv.ErrSyntax},
+		{"0x1e1", Float128{}, strconv.ErrSyntax},
+
+		// overflow
+		{"1.2e4932", Inf(1), strconv.ErrRange},
+		{"-1e5000", Inf(-1), strconv.ErrRange},
+		{"1e100000000000", Inf(1), strconv.ErrRange},
+		{"0x1p16384", Inf(1), strconv.ErrRange},
+		{"-0x1.ffffffffffffffffffffffffffff8p+16383", Inf(-1), strconv.ErrRange},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		var numErr *strconv.NumError
+		if !errors.As(err, &numErr) {
+			t.Errorf("Parse(%q) returns %v, want *strconv.NumError", tt.input, err)
+			continue
+		}
+		if numErr.Num != tt.input || numErr.Err != tt.err {
+			t.Errorf("Parse(%q) returns %v, want %v", tt.input, err, tt.err)
+		}
+		if !equals(got, tt.want) {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, dump(got), dump(tt.want))
+		}
+	}
+}
+
+func TestParse_GoString(t *testing.T) {
+	r := newXoshiro256pp()
+	n := 10000
+	if testing.Short() {
+		n = 1000
+	}
+	for i := 0; i < n; i++ {
+		a, b := r.Float128Pair()
+		b.h &^= mask128 << (shift128 - 64) // make it subnormal
+		for _, f := range []Float128{a, b} {
+			if f.IsNaN() {
+				continue
+			}
+			s := f.GoString()
+			got, err := Parse(s)
+			if err != nil {
+				t.Errorf("Parse(%q) returns error: %v", s, err)
+				continue
+			}
+			if !equals(got, f) {
+				t.Errorf("Parse(%q) = %s, want %s", s, dump(got), dump(f))
+			}
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	var a, b Float128
+	var c int
+	n, err := fmt.Sscan("0.1 -Inf 42", &a, &b, &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("n = %d, want 3", n)
+	}
+	if want := (Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}); !equals(a, want) {
+		t.Errorf("a = %s, want %s", dump(a), dump(want))
+	}
+	if want := Inf(-1); !equals(b, want) {
+		t.Errorf("b = %s, want %s", dump(b), dump(want))
+	}
+	if c != 42 {
+		t.Errorf("c = %d, want 42", c)
+	}
+
+	n, err = fmt.Sscanf("x=1.5e+1, y=-2", "x=%g, y=%e", &a, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("n = %d, want 2", n)
+	}
+	if want := (Float128{0x4002_e000_0000_0000, 0}); !equals(a, want) {
+		t.Errorf("a = %s, want %s", dump(a), dump(want))
+	}
+	if want := (Float128{0xc000_0000_0000_0000, 0}); !equals(b, want) {
+		t.Errorf("b = %s, want %s", dump(b), dump(want))
+	}
+}
+
+func TestScan_Rest(t *testing.T) {
+	// Scan stops at the end of the number, as fmt does for float64.
+	tests := []struct {
+		input string
+		want  Float128
+		rest  string
+	}{
+		{"1.5x", Float128{0x3fff_8000_0000_0000, 0}, "x"},
+		{"1.5+2", Float128{0x3fff_8000_0000_0000, 0}, "+2"},
+		{"1.5.5", Float128{0x3fff_8000_0000_0000, 0}, ".5"},
+		{"1e+5e", Float128{0x400f_86a0_0000_0000, 0}, "e"},
+		{"-0x1p-2y", Float128{0xbffd_0000_0000_0000, 0}, "y"},
+		{"0x1.8p1g", Float128{0x4000_8000_0000_0000, 0}, "g"},
+		{"infx", Inf(1), "x"},
+		{"+inf,", Inf(1), ","},
+		{"-Infinity?", Inf(-1), "?"},
+		{"nan1", NaN(), "1"},
+	}
+
+	for _, tt := range tests {
+		var got Float128
+		var rest string
+		if _, err := fmt.Sscanf(tt.input, "%v%s", &got, &rest); err != nil {
+			t.Errorf("Sscanf(%q) returns error: %v", tt.input, err)
+			continue
+		}
+		if !equals(got, tt.want) || rest != tt.rest {
+			t.Errorf("Sscanf(%q) = %s, %q, want %s, %q", tt.input, dump(got), rest, dump(tt.want), tt.rest)
+		}
+	}
+}
+
+func TestScan_Error(t *testing.T) {
+	var f Float128
+	if _, err := fmt.Sscan("1e", &f); err == nil {
+		t.Error("want error, got nil")
+	}
+	if _, err := fmt.Sscan("x", &f); err == nil {
+		t.Error("want error, got nil")
+	}
+	if _, err := fmt.Sscanf("1", "%d", &f); err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	b.Run("short", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Parse("1.5")
+		}
+	})
+	b.Run("long", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Parse("3.141592653589793238462643383279502884197")
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package float128
+
+// Remainder returns the IEEE 754 floating-point remainder of x/y,
+// that is x - n×y where n is the integer nearest to x/y, rounding ties to even.
+// The result is always exact.
+//
+// Special cases are the same as [math.Remainder]:
+//
+//	Remainder(±Inf, y) = NaN
+//	Remainder(NaN, y) = NaN
+//	Remainder(x, 0) = NaN
+//	Remainder(x, ±Inf) = x
+//	Remainder(x, NaN) = NaN
+func (x Float128) Remainder(y Float128) Float128 {
+	f, _ := x.RemainderFlags(y)
+	return f
+}
+
+// RemQuo returns the same remainder as [Float128.Remainder],
+// and the low bits of the integer quotient n = x/y rounded to nearest even.
+// The sign of quo is the sign of x/y, and its magnitude is congruent to |n| modulo 2^31.
+// It is useful for the argument reduction of periodic functions.
+//
+// If the remainder is NaN, quo is zero.
+func (x Float128) RemQuo(y Float128) (r Float128, quo int) {
+	if !x.remFinite(y) {
+		r, _ = x.RemainderFlags(y)
+		return r, 0
+	}
+	r, n := remainder(x, y, true)
+	quo = int(n & (1<<31 - 1))
+	if (x.h^y.h)&signMask128H != 0 {
+		quo = -quo
+	}
+	return r, quo
+}
+
+// Mod returns the floating-point remainder of x/y, like [math.Mod].
+// The result is x - n×y where n is x/y truncated toward zero,
+// so it has the same sign as x and its magnitude is less than the magnitude of y.
+// The result is always exact.
+//
+// Special cases are the same as [math.Mod]:
+//
+//	Mod(±Inf, y) = NaN
+//	Mod(NaN, y) = NaN
+//	Mod(x, 0) = NaN
+//	Mod(x, ±Inf) = x
+//	Mod(x, NaN) = NaN
+func (x Float128) Mod(y Float128) Float128 {
+	if !x.remFinite(y) {
+		f, _ := x.RemainderFlags(y)
+		return f
+	}
+	r, _ := remainder(x, y, false)
+	return r
+}
+
+// remFinite reports whether the remainder of x/y is a finite number that [remainder] computes.
+func (x Float128) remFinite(y Float128) bool {
+	return x.IsFinite() && y.IsFinite() && !y.isZero()
+}
+
+// remainder returns x - n×y and the low 64 bits of |n|,
+// where n is x/y rounded to an integer, to nearest even if nearest is true, toward zero otherwise.
+// x and y must be finite, and y must not be zero.
+func remainder(x, y Float128, nearest bool) (r Float128, n uint64) {
+	if x.isZero() {
+		return x, 0
+	}
+	sign, expX, fracX := x.split()
+	_, expY, fracY := y.split()
+
+	// the remainder is computed in the unit of 2^(expY-shift128-1), i.e. the half of the last place of y.
+	// x is fracX×2^d, and y is div in this unit.
+	d := expX - expY + 1
+	if d < 0 {
+		// |x| < |y|/2
+		return x, 0
+	}
+	div := fracY.Lsh(1)
+
+	// long division, one bit of the quotient at a time.
+	// The quotient has at most 32,000 bits, but we only need the remainder and the low bits of the quotient.
+	rem := fracX
+	for {
+		n <<= 1
+		if rem.Cmp(div) >= 0 {
+			rem = rem.Sub(div)
+			n |= 1
+		}
+		if d == 0 {
+			break
+		}
+		d--
+		rem = rem.Lsh(1)
+	}
+
+	if nearest {
+		// fracY is |y|/2 in the unit.
+		if c := rem.Cmp(fracY); c > 0 || c == 0 && n&1 != 0 {
+			rem = div.Sub(rem)
+			sign ^= signMask128H
+			n++
+		}
+	}
+
+	// the remainder is exactly representable, so packRound doesn't round it.
+	r, _, _ = packRound(sign, expY-shift128-1, rem, 0, ToNearestEven)
+	return r, n
+}
@@ -0,0 +1,146 @@
+package float128
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRemainder(t *testing.T) {
+	tests := []struct {
+		x, y Float128
+		want Float128
+		quo  int
+	}{
+		// Remainder(5, 3) = -1, 5 = 2×3 - 1
+		{Float128{0x4001_4000_0000_0000, 0}, Float128{0x4000_8000_0000_0000, 0}, Float128{0xbfff_0000_0000_0000, 0}, 2},
+		// Remainder(-5, 3) = 1
+		{Float128{0xc001_4000_0000_0000, 0}, Float128{0x4000_8000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 0}, -2},
+		// Remainder(5, -3) = -1
+		{Float128{0x4001_4000_0000_0000, 0}, Float128{0xc000_8000_0000_0000, 0}, Float128{0xbfff_0000_0000_0000, 0}, -2},
+		// Remainder(4, 3) = 1
+		{Float128{0x4001_0000_0000_0000, 0}, Float128{0x4000_8000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 0}, 1},
+
+		// ties to even
+		// Remainder(1, 2) = 1, 1 = 0×2 + 1
+		{Float128{0x3fff_0000_0000_0000, 0}, Float128{0x4000_0000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 0}, 0},
+		// Remainder(3, 2) = -1, 3 = 2×2 - 1
+		{Float128{0x4000_8000_0000_0000, 0}, Float128{0x4000_0000_0000_0000, 0}, Float128{0xbfff_0000_0000_0000, 0}, 2},
+		// Remainder(5, 2) = 1, 5 = 2×2 + 1
+		{Float128{0x4001_4000_0000_0000, 0}, Float128{0x4000_0000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 0}, 2},
+
+		// |x| < |y|/2
+		{Float128{0x3ffd_0000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 0}, Float128{0x3ffd_0000_0000_0000, 0}, 0},
+		// |x| > |y|/2
+		{Float128{0x3ffe_8000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 0}, Float128{0xbffd_0000_0000_0000, 0}, 1},
+
+		// zero results have the sign of x
+		{Float128{0x4001_0000_0000_0000, 0}, Float128{0x4000_0000_0000_0000, 0}, Float128{0x0000_0000_0000_0000, 0}, 2},
+		{Float128{0xc001_0000_0000_0000, 0}, Float128{0x4000_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 0}, -2},
+		{Float128{0x8000_0000_0000_0000, 0}, Float128{0x4000_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 0}, 0},
+
+		// the exponents differ by more than 32,000.
+		// Remainder(MaxFloat128, SmallestNonzeroFloat128) = 0
+		{Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, Float128{0, 1}, Float128{}, 0},
+		// Remainder(MaxFloat128, 3×SmallestNonzeroFloat128) = -SmallestNonzeroFloat128,
+		// because MaxFloat128 = (2^113-1)×2^(16271+16494)×SmallestNonzeroFloat128 and (2^113-1)×2^32765 ≡ 2 (mod 3).
+		{Float128{0x7ffe_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff}, Float128{0, 3}, Float128{0x8000_0000_0000_0000, 1}, 0x2aaaaaab},
+		// Remainder(2^16383, 3×SmallestNonzeroFloat128) = -SmallestNonzeroFloat128,
+		// because 2^(16383+16494) ≡ 2 (mod 3).
+		{Float128{0x7ffe_0000_0000_0000, 0}, Float128{0, 3}, Float128{0x8000_0000_0000_0000, 1}, 0x2aaaaaab},
+		// subnormal results
+		{Float128{0x0000_0000_0000_0000, 5}, Float128{0x0000_0000_0000_0000, 3}, Float128{0x8000_0000_0000_0000, 1}, 2},
+		{Float128{0x0001_0000_0000_0001, 0}, Float128{0x0001_0000_0000_0000, 0}, Float128{0x0000_0000_0000_0001, 0}, 1},
+
+		// special cases
+		{Inf(1), Float128{0x3fff_0000_0000_0000, 0}, NaN(), 0},
+		{Inf(-1), Float128{0x3fff_0000_0000_0000, 0}, NaN(), 0},
+		{Float128{0x3fff_0000_0000_0000, 0}, Float128{}, NaN(), 0},
+		{Float128{0x3fff_0000_0000_0000, 0}, Inf(1), Float128{0x3fff_0000_0000_0000, 0}, 0},
+		{Float128{0xbfff_0000_0000_0000, 0}, Inf(-1), Float128{0xbfff_0000_0000_0000, 0}, 0},
+		{NaN(), Float128{0x3fff_0000_0000_0000, 0}, NaN(), 0},
+		{Float128{0x3fff_0000_0000_0000, 0}, NaN(), NaN(), 0},
+	}
+
+	for _, tt := range tests {
+		got := tt.x.Remainder(tt.y)
+		if got != tt.want {
+			t.Errorf("%s.Remainder(%s) = %s, want %s", dump(tt.x), dump(tt.y), dump(got), dump(tt.want))
+		}
+
+		got, quo := tt.x.RemQuo(tt.y)
+		if got != tt.want || quo != tt.quo {
+			t.Errorf("%s.RemQuo(%s) = %s, %#x, want %s, %#x", dump(tt.x), dump(tt.y), dump(got), quo, dump(tt.want), tt.quo)
+		}
+
+		got, flags := tt.x.RemainderFlags(tt.y)
+		if got != tt.want {
+			t.Errorf("%s.RemainderFlags(%s) = %s, want %s", dump(tt.x), dump(tt.y), dump(got), dump(tt.want))
+		}
+		var wantFlags Flags
+		if tt.want.IsNaN() && !tt.x.IsNaN() && !tt.y.IsNaN() {
+			wantFlags = FlagInvalid
+		}
+		if flags != wantFlags {
+			t.Errorf("%s.RemainderFlags(%s): got flags %s, want %s", dump(tt.x), dump(tt.y), flags, wantFlags)
+		}
+	}
+}
+
+func TestMod(t *testing.T) {
+	tests := []struct {
+		x, y Float128
+		want Float128
+	}{
+		// Mod(5, 3) = 2
+		{Float128{0x4001_4000_0000_0000, 0}, Float128{0x4000_8000_0000_0000, 0}, Float128{0x4000_0000_0000_0000, 0}},
+		// Mod(-5, 3) = -2
+		{Float128{0xc001_4000_0000_0000, 0}, Float128{0x4000_8000_0000_0000, 0}, Float128{0xc000_0000_0000_0000, 0}},
+		// Mod(5, -3) = 2
+		{Float128{0x4001_4000_0000_0000, 0}, Float128{0xc000_8000_0000_0000, 0}, Float128{0x4000_0000_0000_0000, 0}},
+		// Mod(0.75, 1) = 0.75
+		{Float128{0x3ffe_8000_0000_0000, 0}, Float128{0x3fff_0000_0000_0000, 0}, Float128{0x3ffe_8000_0000_0000, 0}},
+		// Mod(-4, 2) = -0
+		{Float128{0xc001_0000_0000_0000, 0}, Float128{0x4000_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 0}},
+		// Mod(2^16383, 3×SmallestNonzeroFloat128) = 2×SmallestNonzeroFloat128
+		{Float128{0x7ffe_0000_0000_0000, 0}, Float128{0, 3}, Float128{0, 2}},
+
+		// special cases
+		{Inf(1), Float128{0x3fff_0000_0000_0000, 0}, NaN()},
+		{Float128{0x3fff_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 0}, NaN()},
+		{Float128{0x3fff_0000_0000_0000, 0}, Inf(-1), Float128{0x3fff_0000_0000_0000, 0}},
+		{NaN(), Float128{0x3fff_0000_0000_0000, 0}, NaN()},
+		{Float128{0x3fff_0000_0000_0000, 0}, NaN(), NaN()},
+	}
+
+	for _, tt := range tests {
+		got := tt.x.Mod(tt.y)
+		if got != tt.want {
+			t.Errorf("%s.Mod(%s) = %s, want %s", dump(tt.x), dump(tt.y), dump(got), dump(tt.want))
+		}
+	}
+}
+
+func TestRemainder_Float64(t *testing.T) {
+	// the remainders of float64 numbers are exactly representable in float64,
+	// so the results agree with the math package.
+	r := newXoshiro256pp()
+	for i := 0; i < 100000; i++ {
+		a := math.Float64frombits(r.Uint64())
+		b := math.Float64frombits(r.Uint64())
+		if i%2 == 0 {
+			// make the exponents close to each other.
+			b = math.Float64frombits(math.Float64bits(a)&0xfff0_0000_0000_0000 ^ r.Uint64()&0x801f_ffff_ffff_ffff)
+		}
+		x, y := FromFloat64(a), FromFloat64(b)
+
+		got, want := x.Remainder(y).Float64(), math.Remainder(a, b)
+		if math.Float64bits(got) != math.Float64bits(want) && !(math.IsNaN(got) && math.IsNaN(want)) {
+			t.Errorf("Remainder(%x, %x) = %x, want %x", a, b, got, want)
+		}
+
+		got, want = x.Mod(y).Float64(), math.Mod(a, b)
+		if math.Float64bits(got) != math.Float64bits(want) && !(math.IsNaN(got) && math.IsNaN(want)) {
+			t.Errorf("Mod(%x, %x) = %x, want %x", a, b, got, want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package float128
+
+import "math/big"
+
+// Floor returns the greatest integer value less than or equal to f.
+//
+// Special cases are:
+//
+//	Floor(±0) = ±0
+//	Floor(±Inf) = ±Inf
+//	Floor(NaN) = NaN
+func (f Float128) Floor() Float128 {
+	r, _ := f.RoundToIntFlags(ToNegativeInf)
+	return r
+}
+
+// Ceil returns the least integer value greater than or equal to f.
+//
+// Special cases are:
+//
+//	Ceil(±0) = ±0
+//	Ceil(±Inf) = ±Inf
+//	Ceil(NaN) = NaN
+func (f Float128) Ceil() Float128 {
+	r, _ := f.RoundToIntFlags(ToPositiveInf)
+	return r
+}
+
+// Trunc returns the integer value of f.
+//
+// Special cases are:
+//
+//	Trunc(±0) = ±0
+//	Trunc(±Inf) = ±Inf
+//	Trunc(NaN) = NaN
+func (f Float128) Trunc() Float128 {
+	r, _ := f.RoundToIntFlags(ToZero)
+	return r
+}
+
+// Round returns the nearest integer, rounding half away from zero.
+//
+// Special cases are:
+//
+//	Round(±0) = ±0
+//	Round(±Inf) = ±Inf
+//	Round(NaN) = NaN
+func (f Float128) Round() Float128 {
+	r, _ := f.RoundToIntFlags(ToNearestAway)
+	return r
+}
+
+// RoundToEven returns the nearest integer, rounding ties to even.
+//
+// Special cases are:
+//
+//	RoundToEven(±0) = ±0
+//	RoundToEven(±Inf) = ±Inf
+//	RoundToEven(NaN) = NaN
+func (f Float128) RoundToEven() Float128 {
+	r, _ := f.RoundToIntFlags(ToNearestEven)
+	return r
+}
+
+// Modf returns integer and fractional floating-point numbers that sum to f.
+// Both values have the same sign as f.
+//
+// Special cases are:
+//
+//	Modf(±Inf) = ±Inf, NaN
+//	Modf(NaN) = NaN, NaN
+func (f Float128) Modf() (int Float128, frac Float128) {
+	switch {
+	case f.IsNaN():
+		f = propagateNaN(f, f)
+		return f, f
+	case f.IsInf(0):
+		return f, nan
+	case f.isZero():
+		return f, f
+	}
+
+	sign, exp, fracF := f.split()
+	if exp >= shift128 {
+		// f is an integer.
+		return f, Float128{sign, 0}
+	}
+	if exp < 0 {
+		// |f| < 1
+		return Float128{sign, 0}, f
+	}
+
+	// clear the fractional bits of f.
+	mask := one.Lsh(uint(shift128 - exp)).Sub(one)
+	int = Float128{f.h &^ mask.H, f.l &^ mask.L}
+
+	// the fractional part is exactly representable.
+	frac, _, _ = packRound(sign, exp-shift128, fracF.And(mask), 0, ToNearestEven)
+	return int, frac
+}
+
+// RoundToIntFlags returns f rounded to an integer in the rounding mode,
+// and the raised exception flags.
+// It is roundToIntegralExact of IEEE 754, so it raises [FlagInexact] if the result differs from f.
+//
+// [Float128.Floor], [Float128.Ceil], [Float128.Trunc], [Float128.Round] and [Float128.RoundToEven]
+// are the same as RoundToIntFlags in the rounding modes
+// ToNegativeInf, ToPositiveInf, ToZero, ToNearestAway and ToNearestEven respectively,
+// but they don't report the flags.
+func (f Float128) RoundToIntFlags(mode RoundingMode) (Float128, Flags) {
+	switch {
+	case f.IsNaN():
+		return propagateNaNFlags(f, f)
+	case f.IsInf(0) || f.isZero():
+		return f, 0
+	}
+
+	sign, exp, frac := f.split()
+	if exp >= shift128 {
+		// f is an integer.
+		return f, 0
+	}
+
+	q, acc := roundShift(sign, frac, 0, uint(shift128-exp), mode)
+	if acc == big.Exact {
+		return f, 0
+	}
+
+	// q is an integer at most 2^113, so packRound doesn't round it.
+	// if q is zero, the result is zero with the sign of f.
+	r, _, _ := packRound(sign, 0, q, 0, ToNearestEven)
+	return r, FlagInexact
+}
@@ -0,0 +1,278 @@
+package float128
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRoundToInt(t *testing.T) {
+	tests := []struct {
+		input                           Float128
+		floor, ceil, trunc, round, even Float128
+	}{
+		// 1
+		{
+			Float128{0x3fff_0000_0000_0000, 0},
+			Float128{0x3fff_0000_0000_0000, 0},
+			Float128{0x3fff_0000_0000_0000, 0},
+			Float128{0x3fff_0000_0000_0000, 0},
+			Float128{0x3fff_0000_0000_0000, 0},
+			Float128{0x3fff_0000_0000_0000, 0},
+		},
+		// 1.5
+		{
+			Float128{0x3fff_8000_0000_0000, 0},
+			Float128{0x3fff_0000_0000_0000, 0}, // 1
+			Float128{0x4000_0000_0000_0000, 0}, // 2
+			Float128{0x3fff_0000_0000_0000, 0}, // 1
+			Float128{0x4000_0000_0000_0000, 0}, // 2
+			Float128{0x4000_0000_0000_0000, 0}, // 2
+		},
+		// 2.5
+		{
+			Float128{0x4000_4000_0000_0000, 0},
+			Float128{0x4000_0000_0000_0000, 0}, // 2
+			Float128{0x4000_8000_0000_0000, 0}, // 3
+			Float128{0x4000_0000_0000_0000, 0}, // 2
+			Float128{0x4000_8000_0000_0000, 0}, // 3
+			Float128{0x4000_0000_0000_0000, 0}, // 2
+		},
+		// -2.5
+		{
+			Float128{0xc000_4000_0000_0000, 0},
+			Float128{0xc000_8000_0000_0000, 0}, // -3
+			Float128{0xc000_0000_0000_0000, 0}, // -2
+			Float128{0xc000_0000_0000_0000, 0}, // -2
+			Float128{0xc000_8000_0000_0000, 0}, // -3
+			Float128{0xc000_0000_0000_0000, 0}, // -2
+		},
+		// 0.5
+		{
+			Float128{0x3ffe_0000_0000_0000, 0},
+			Float128{0x0000_0000_0000_0000, 0}, // +0
+			Float128{0x3fff_0000_0000_0000, 0}, // 1
+			Float128{0x0000_0000_0000_0000, 0}, // +0
+			Float128{0x3fff_0000_0000_0000, 0}, // 1
+			Float128{0x0000_0000_0000_0000, 0}, // +0
+		},
+		// -0.5
+		{
+			Float128{0xbffe_0000_0000_0000, 0},
+			Float128{0xbfff_0000_0000_0000, 0}, // -1
+			Float128{0x8000_0000_0000_0000, 0}, // -0
+			Float128{0x8000_0000_0000_0000, 0}, // -0
+			Float128{0xbfff_0000_0000_0000, 0}, // -1
+			Float128{0x8000_0000_0000_0000, 0}, // -0
+		},
+		// 0.5000000000000000000000000000000001
+		{
+			Float128{0x3ffe_0000_0000_0000, 1},
+			Float128{0x0000_0000_0000_0000, 0}, // +0
+			Float128{0x3fff_0000_0000_0000, 0}, // 1
+			Float128{0x0000_0000_0000_0000, 0}, // +0
+			Float128{0x3fff_0000_0000_0000, 0}, // 1
+			Float128{0x3fff_0000_0000_0000, 0}, // 1
+		},
+		// the smallest positive subnormal number
+		{
+			Float128{0, 1},
+			Float128{0x0000_0000_0000_0000, 0}, // +0
+			Float128{0x3fff_0000_0000_0000, 0}, // 1
+			Float128{0x0000_0000_0000_0000, 0}, // +0
+			Float128{0x0000_0000_0000_0000, 0}, // +0
+			Float128{0x0000_0000_0000_0000, 0}, // +0
+		},
+		// 2^112 - 0.5, the largest number which has the fractional part
+		{
+			Float128{0x406e_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff},
+			Float128{0x406e_ffff_ffff_ffff, 0xffff_ffff_ffff_fffe}, // 2^112 - 1
+			Float128{0x406f_0000_0000_0000, 0},                     // 2^112
+			Float128{0x406e_ffff_ffff_ffff, 0xffff_ffff_ffff_fffe}, // 2^112 - 1
+			Float128{0x406f_0000_0000_0000, 0},                     // 2^112
+			Float128{0x406f_0000_0000_0000, 0},                     // 2^112
+		},
+		// 2^112 + 2, an integer
+		{
+			Float128{0x406f_0000_0000_0000, 1},
+			Float128{0x406f_0000_0000_0000, 1},
+			Float128{0x406f_0000_0000_0000, 1},
+			Float128{0x406f_0000_0000_0000, 1},
+			Float128{0x406f_0000_0000_0000, 1},
+			Float128{0x406f_0000_0000_0000, 1},
+		},
+		// ±0
+		{
+			Float128{0x0000_0000_0000_0000, 0},
+			Float128{0x0000_0000_0000_0000, 0},
+			Float128{0x0000_0000_0000_0000, 0},
+			Float128{0x0000_0000_0000_0000, 0},
+			Float128{0x0000_0000_0000_0000, 0},
+			Float128{0x0000_0000_0000_0000, 0},
+		},
+		{
+			Float128{0x8000_0000_0000_0000, 0},
+			Float128{0x8000_0000_0000_0000, 0},
+			Float128{0x8000_0000_0000_0000, 0},
+			Float128{0x8000_0000_0000_0000, 0},
+			Float128{0x8000_0000_0000_0000, 0},
+			Float128{0x8000_0000_0000_0000, 0},
+		},
+		// ±Inf
+		{Inf(1), Inf(1), Inf(1), Inf(1), Inf(1), Inf(1)},
+		{Inf(-1), Inf(-1), Inf(-1), Inf(-1), Inf(-1), Inf(-1)},
+		// NaN
+		{
+			Float128{0xffff_0000_0000_0000, 42},
+			Float128{0xffff_8000_0000_0000, 42},
+			Float128{0xffff_8000_0000_0000, 42},
+			Float128{0xffff_8000_0000_0000, 42},
+			Float128{0xffff_8000_0000_0000, 42},
+			Float128{0xffff_8000_0000_0000, 42},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.input.Floor(); got != tt.floor {
+			t.Errorf("%s.Floor() = %s, want %s", dump(tt.input), dump(got), dump(tt.floor))
+		}
+		if got := tt.input.Ceil(); got != tt.ceil {
+			t.Errorf("%s.Ceil() = %s, want %s", dump(tt.input), dump(got), dump(tt.ceil))
+		}
+		if got := tt.input.Trunc(); got != tt.trunc {
+			t.Errorf("%s.Trunc() = %s, want %s", dump(tt.input), dump(got), dump(tt.trunc))
+		}
+		if got := tt.input.Round(); got != tt.round {
+			t.Errorf("%s.Round() = %s, want %s", dump(tt.input), dump(got), dump(tt.round))
+		}
+		if got := tt.input.RoundToEven(); got != tt.even {
+			t.Errorf("%s.RoundToEven() = %s, want %s", dump(tt.input), dump(got), dump(tt.even))
+		}
+	}
+}
+
+func TestRoundToIntFlags(t *testing.T) {
+	tests := []struct {
+		input Float128
+		mode  RoundingMode
+		want  Float128
+		flags Flags
+	}{
+		// 2.5
+		{Float128{0x4000_4000_0000_0000, 0}, ToNearestEven, Float128{0x4000_0000_0000_0000, 0}, FlagInexact},
+		{Float128{0x4000_4000_0000_0000, 0}, ToNearestAway, Float128{0x4000_8000_0000_0000, 0}, FlagInexact},
+		{Float128{0x4000_4000_0000_0000, 0}, ToZero, Float128{0x4000_0000_0000_0000, 0}, FlagInexact},
+		{Float128{0x4000_4000_0000_0000, 0}, AwayFromZero, Float128{0x4000_8000_0000_0000, 0}, FlagInexact},
+		{Float128{0x4000_4000_0000_0000, 0}, ToNegativeInf, Float128{0x4000_0000_0000_0000, 0}, FlagInexact},
+		{Float128{0x4000_4000_0000_0000, 0}, ToPositiveInf, Float128{0x4000_8000_0000_0000, 0}, FlagInexact},
+		{Float128{0x4000_4000_0000_0000, 0}, ToOdd, Float128{0x4000_8000_0000_0000, 0}, FlagInexact},
+
+		// -0.25
+		{Float128{0xbffd_0000_0000_0000, 0}, ToNearestEven, Float128{0x8000_0000_0000_0000, 0}, FlagInexact},
+		{Float128{0xbffd_0000_0000_0000, 0}, AwayFromZero, Float128{0xbfff_0000_0000_0000, 0}, FlagInexact},
+		{Float128{0xbffd_0000_0000_0000, 0}, ToNegativeInf, Float128{0xbfff_0000_0000_0000, 0}, FlagInexact},
+		{Float128{0xbffd_0000_0000_0000, 0}, ToPositiveInf, Float128{0x8000_0000_0000_0000, 0}, FlagInexact},
+		{Float128{0xbffd_0000_0000_0000, 0}, ToOdd, Float128{0xbfff_0000_0000_0000, 0}, FlagInexact},
+
+		// 3.75
+		{Float128{0x4000_e000_0000_0000, 0}, ToOdd, Float128{0x4000_8000_0000_0000, 0}, FlagInexact},
+
+		// exact
+		{Float128{0x4000_8000_0000_0000, 0}, ToPositiveInf, Float128{0x4000_8000_0000_0000, 0}, 0},
+		{Float128{0x8000_0000_0000_0000, 0}, ToPositiveInf, Float128{0x8000_0000_0000_0000, 0}, 0},
+		{Inf(-1), ToZero, Inf(-1), 0},
+
+		// NaN
+		{Float128{0x7fff_0000_0000_0000, 1}, ToZero, Float128{0x7fff_8000_0000_0000, 1}, FlagInvalid},
+		{Float128{0x7fff_8000_0000_0000, 1}, ToZero, Float128{0x7fff_8000_0000_0000, 1}, 0},
+	}
+
+	for _, tt := range tests {
+		got, flags := tt.input.RoundToIntFlags(tt.mode)
+		if got != tt.want || flags != tt.flags {
+			t.Errorf("%s.RoundToIntFlags(%s) = %s, %s, want %s, %s", dump(tt.input), tt.mode, dump(got), flags, dump(tt.want), tt.flags)
+		}
+
+		ctx := &Context{Mode: tt.mode}
+		if got := ctx.RoundToInt(tt.input); got != tt.want || ctx.Flags != tt.flags {
+			t.Errorf("Context{Mode: %s}.RoundToInt(%s) = %s, %s, want %s, %s", tt.mode, dump(tt.input), dump(got), ctx.Flags, dump(tt.want), tt.flags)
+		}
+	}
+}
+
+func TestRoundToInt_Float64(t *testing.T) {
+	// the results agree with the math package.
+	tests := []struct {
+		name string
+		f    func(Float128) Float128
+		want func(float64) float64
+	}{
+		{"Floor", Float128.Floor, math.Floor},
+		{"Ceil", Float128.Ceil, math.Ceil},
+		{"Trunc", Float128.Trunc, math.Trunc},
+		{"Round", Float128.Round, math.Round},
+		{"RoundToEven", Float128.RoundToEven, math.RoundToEven},
+	}
+
+	r := newXoshiro256pp()
+	for i := 0; i < 100000; i++ {
+		var a float64
+		if i%2 == 0 {
+			// the numbers around 1, which have the fractional parts.
+			a = math.Float64frombits(r.Uint64()&0x800f_ffff_ffff_ffff | uint64(0x3fd+i%60)<<52)
+		} else {
+			a = math.Float64frombits(r.Uint64())
+		}
+		x := FromFloat64(a)
+
+		for _, tt := range tests {
+			got, want := tt.f(x).Float64(), tt.want(a)
+			if math.Float64bits(got) != math.Float64bits(want) && !(math.IsNaN(got) && math.IsNaN(want)) {
+				t.Errorf("%s(%x) = %x, want %x", tt.name, a, got, want)
+			}
+		}
+
+		gotInt, gotFrac := x.Modf()
+		wantInt, wantFrac := math.Modf(a)
+		if math.Float64bits(gotInt.Float64()) != math.Float64bits(wantInt) && !math.IsNaN(wantInt) ||
+			math.Float64bits(gotFrac.Float64()) != math.Float64bits(wantFrac) && !math.IsNaN(wantFrac) {
+			t.Errorf("Modf(%x) = %x, %x, want %x, %x", a, gotInt.Float64(), gotFrac.Float64(), wantInt, wantFrac)
+		}
+	}
+}
+
+func TestModf(t *testing.T) {
+	tests := []struct {
+		input     Float128
+		int, frac Float128
+	}{
+		// 2.5
+		{Float128{0x4000_4000_0000_0000, 0}, Float128{0x4000_0000_0000_0000, 0}, Float128{0x3ffe_0000_0000_0000, 0}},
+		// -2.5
+		{Float128{0xc000_4000_0000_0000, 0}, Float128{0xc000_0000_0000_0000, 0}, Float128{0xbffe_0000_0000_0000, 0}},
+		// -3
+		{Float128{0xc000_8000_0000_0000, 0}, Float128{0xc000_8000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 0}},
+		// -0.5
+		{Float128{0xbffe_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 0}, Float128{0xbffe_0000_0000_0000, 0}},
+		// 1 + 2^-112, the smallest fractional part of the numbers greater than one
+		{Float128{0x3fff_0000_0000_0000, 1}, Float128{0x3fff_0000_0000_0000, 0}, Float128{0x3f8f_0000_0000_0000, 0}},
+		// 2^112 - 0.5
+		{
+			Float128{0x406e_ffff_ffff_ffff, 0xffff_ffff_ffff_ffff},
+			Float128{0x406e_ffff_ffff_ffff, 0xffff_ffff_ffff_fffe},
+			Float128{0x3ffe_0000_0000_0000, 0},
+		},
+		// subnormal
+		{Float128{0x8000_0000_0000_0000, 1}, Float128{0x8000_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 1}},
+		// special cases
+		{Float128{0x8000_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 0}, Float128{0x8000_0000_0000_0000, 0}},
+		{Inf(-1), Inf(-1), NaN()},
+		{NaN(), NaN(), NaN()},
+	}
+
+	for _, tt := range tests {
+		gotInt, gotFrac := tt.input.Modf()
+		if gotInt != tt.int || gotFrac != tt.frac {
+			t.Errorf("%s.Modf() = %s, %s, want %s, %s", dump(tt.input), dump(gotInt), dump(gotFrac), dump(tt.int), dump(tt.frac))
+		}
+	}
+}
@@ -5,7 +5,9 @@ set -eux
 SEED=${GITHUB_RUN_ID:-$(date +%s)}
 echo "$SEED"
 
+# the rest of the arguments are the options of testfloat_gen, e.g. -rminMag -exact.
 TEST_NAME=$1
+shift
 ROOT=$(cd "$(dirname "$0")"; cd ..; pwd)
 cd "$ROOT"
-timeout 305m "$ROOT/bin/testfloat_gen" -level 2 -seed "$SEED" -forever "$TEST_NAME" | go run ./internal/cmd/float_test "$TEST_NAME"
+timeout 305m "$ROOT/bin/testfloat_gen" -level 2 -seed "$SEED" -forever "$@" "$TEST_NAME" | go run ./internal/cmd/float_test "$TEST_NAME" "$@"
@@ -0,0 +1,17 @@
+#!/usr/bin/env bash
+
+# run the tests with the test vectors generated by ./internal/cmd/testfloat_gen.
+# unlike run_test.sh, it doesn't need Berkeley SoftFloat and TestFloat.
+
+set -eux
+
+SEED=${GITHUB_RUN_ID:-$(date +%s)}
+echo "$SEED"
+
+# the rest of the arguments are the options of testfloat_gen, e.g. -rminMag -exact.
+TEST_NAME=$1
+shift
+COUNT=${COUNT:-1000000}
+ROOT=$(cd "$(dirname "$0")"; cd ..; pwd)
+cd "$ROOT"
+go run ./internal/cmd/testfloat_gen -seed "$SEED" -n "$COUNT" "$@" "$TEST_NAME" | go run ./internal/cmd/float_test "$TEST_NAME" "$@"
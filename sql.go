@@ -0,0 +1,122 @@
+package float128
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+// StorageMode is the representation of Float128 values in databases.
+type StorageMode int
+
+const (
+	// StorageText stores values as text with the shortest decimal representation that round-trips.
+	StorageText StorageMode = iota
+
+	// StorageBinary stores values as 16-byte blobs in the format of [Float128.MarshalBinary].
+	StorageBinary
+)
+
+// Value implements [driver.Valuer].
+// It returns the shortest decimal representation of f that round-trips, as a string.
+//
+// Float128 doesn't implement [database/sql.Scanner],
+// because its Scan method implements [fmt.Scanner].
+// Use [SQLScanner] or [NullFloat128] to scan values from databases.
+func (f Float128) Value() (driver.Value, error) {
+	return f.String(), nil
+}
+
+// SQLScanner returns a [database/sql.Scanner] that stores the scanned value into f.
+// It accepts the same values as [NullFloat128.Scan] in StorageText mode, except NULL:
+//
+//	var f float128.Float128
+//	err := row.Scan(float128.SQLScanner(&f))
+func SQLScanner(f *Float128) sql.Scanner {
+	return (*sqlScanner)(f)
+}
+
+// sqlScanner is a Float128 that implements [database/sql.Scanner].
+type sqlScanner Float128
+
+// Scan implements the [database/sql.Scanner] interface.
+func (s *sqlScanner) Scan(value any) error {
+	if value == nil {
+		return errors.New("float128: cannot scan NULL into Float128, use NullFloat128 instead")
+	}
+	f, err := scanValue(value, StorageText)
+	if err != nil {
+		return err
+	}
+	*s = sqlScanner(f)
+	return nil
+}
+
+// NullFloat128 represents a Float128 that may be null.
+// NullFloat128 implements the [database/sql.Scanner] interface so
+// it can be used as a scan destination, similar to [database/sql.NullFloat64].
+type NullFloat128 struct {
+	Float128 Float128
+	Valid    bool // Valid is true if Float128 is not NULL
+
+	// Mode is the representation of Float128 in databases used by [NullFloat128.Value].
+	Mode StorageMode
+}
+
+// Scan implements the [database/sql.Scanner] interface.
+// It accepts:
+//   - nil as NULL.
+//   - string in the syntax of [Parse], including hexadecimal floating-point numbers.
+//   - []byte in the syntax of [Parse] if n.Mode is StorageText,
+//     or in the format of [Float128.MarshalBinary] if n.Mode is StorageBinary.
+//   - float64 and int64 that are converted exactly.
+func (n *NullFloat128) Scan(value any) error {
+	if value == nil {
+		n.Float128, n.Valid = Float128{}, false
+		return nil
+	}
+
+	f, err := scanValue(value, n.Mode)
+	if err != nil {
+		return err
+	}
+	n.Float128, n.Valid = f, true
+	return nil
+}
+
+// scanValue converts the non-nil value from a database driver to Float128.
+func scanValue(value any, mode StorageMode) (Float128, error) {
+	switch v := value.(type) {
+	case string:
+		return Parse(v)
+	case []byte:
+		if mode == StorageBinary {
+			var f Float128
+			err := f.UnmarshalBinary(v)
+			return f, err
+		}
+		return Parse(string(v))
+	case float64:
+		return FromFloat64(v), nil
+	case int64:
+		return FromInt64(v), nil
+	}
+	return Float128{}, fmt.Errorf("float128: cannot scan type %T into Float128", value)
+}
+
+// Value implements the [driver.Valuer] interface.
+// It returns nil if n is not valid.
+// Otherwise, it returns a string if n.Mode is StorageText, or a 16-byte []byte if n.Mode is StorageBinary.
+func (n NullFloat128) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	switch n.Mode {
+	case StorageText:
+		return n.Float128.String(), nil
+	case StorageBinary:
+		return n.Float128.MarshalBinary()
+	}
+	return nil, fmt.Errorf("float128: unknown storage mode %d", n.Mode)
+}
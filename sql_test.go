@@ -0,0 +1,252 @@
+package float128
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+)
+
+// echoDriver is a fake database driver.
+// Its query returns its arguments as a row.
+type echoDriver struct{}
+
+func (echoDriver) Open(name string) (driver.Conn, error) {
+	return echoConn{}, nil
+}
+
+type echoConn struct{}
+
+func (echoConn) Prepare(query string) (driver.Stmt, error) {
+	return echoStmt{}, nil
+}
+
+func (echoConn) Close() error {
+	return nil
+}
+
+func (echoConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("echoDriver: transactions are not supported")
+}
+
+type echoStmt struct{}
+
+func (echoStmt) Close() error {
+	return nil
+}
+
+func (echoStmt) NumInput() int {
+	return -1
+}
+
+func (echoStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+func (echoStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &echoRows{values: args}, nil
+}
+
+type echoRows struct {
+	values []driver.Value
+	done   bool
+}
+
+func (r *echoRows) Columns() []string {
+	return make([]string, len(r.values))
+}
+
+func (r *echoRows) Close() error {
+	return nil
+}
+
+func (r *echoRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.values)
+	return nil
+}
+
+func init() {
+	sql.Register("float128-echo", echoDriver{})
+}
+
+func openEchoDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("float128-echo", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestValue(t *testing.T) {
+	db := openEchoDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		input any
+		want  driver.Value
+	}{
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, "0.1"},
+		{Inf(-1), "-Inf"},
+		{NullFloat128{}, nil},
+		{NullFloat128{Float128: Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, Valid: true}, "0.1"},
+		{
+			NullFloat128{Float128: Float128{0x3fff_0000_0000_0000, 0}, Valid: true, Mode: StorageBinary},
+			[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0x3f},
+		},
+	}
+	for _, tt := range tests {
+		var got any
+		if err := db.QueryRowContext(ctx, "SELECT ?", tt.input).Scan(&got); err != nil {
+			t.Errorf("%v: unexpected error: %v", tt.input, err)
+			continue
+		}
+		switch want := tt.want.(type) {
+		case []byte:
+			if b, ok := got.([]byte); !ok || !bytes.Equal(b, want) {
+				t.Errorf("%v: got %#v, want %#v", tt.input, got, want)
+			}
+		default:
+			if got != want {
+				t.Errorf("%v: got %#v, want %#v", tt.input, got, want)
+			}
+		}
+	}
+}
+
+func TestNullFloat128_Scan(t *testing.T) {
+	db := openEchoDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		input any
+		mode  StorageMode
+		want  NullFloat128
+	}{
+		{nil, StorageText, NullFloat128{}},
+		{"0.1", StorageText, NullFloat128{Float128: Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, Valid: true}},
+		{"0x1.8p+1", StorageText, NullFloat128{Float128: Float128{0x4000_8000_0000_0000, 0}, Valid: true}},
+		{[]byte("-0.5"), StorageText, NullFloat128{Float128: Float128{0xbffe_0000_0000_0000, 0}, Valid: true}},
+		{[]byte("1234567890123456"), StorageText, NullFloat128{Float128: Float128{0x4031_18b5_4f22_aeb0, 0}, Valid: true}},
+		{
+			[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0x3f},
+			StorageBinary,
+			NullFloat128{Float128: Float128{0x3fff_0000_0000_0000, 0}, Valid: true, Mode: StorageBinary},
+		},
+		{
+			// 16 ASCII digits are a valid decimal, but they are binary in StorageBinary mode.
+			[]byte("1234567890123456"),
+			StorageBinary,
+			NullFloat128{Float128: Float128{0x3635_3433_3231_3039, 0x3837_3635_3433_3231}, Valid: true, Mode: StorageBinary},
+		},
+		{"0.1", StorageBinary, NullFloat128{Float128: Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, Valid: true, Mode: StorageBinary}},
+		{0.1, StorageText, NullFloat128{Float128: Float128{0x3ffb_9999_9999_9999, 0xa000_0000_0000_0000}, Valid: true}},
+		{int64(-3), StorageText, NullFloat128{Float128: Float128{0xc000_8000_0000_0000, 0}, Valid: true}},
+	}
+	for _, tt := range tests {
+		got := NullFloat128{Float128: Float128{0x3fff_0000_0000_0000, 0}, Valid: true, Mode: tt.mode}
+		if err := db.QueryRowContext(ctx, "SELECT ?", tt.input).Scan(&got); err != nil {
+			t.Errorf("%v: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%v: got %#v, want %#v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNullFloat128_ScanError(t *testing.T) {
+	db := openEchoDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		input any
+		mode  StorageMode
+	}{
+		{"1.2.3", StorageText},
+		{[]byte("1.2.3"), StorageText},
+		{true, StorageText},
+
+		// 16 bytes are not binary in StorageText mode.
+		{[]byte("1234567890abcdef"), StorageText},
+		{[]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0x3f}, StorageText},
+
+		// the length of binary must be 16.
+		{[]byte("0.1"), StorageBinary},
+	}
+	for _, tt := range tests {
+		got := NullFloat128{Mode: tt.mode}
+		if err := db.QueryRowContext(ctx, "SELECT ?", tt.input).Scan(&got); err == nil {
+			t.Errorf("%v: want error, got nil", tt.input)
+		}
+	}
+}
+
+func TestSQLScanner(t *testing.T) {
+	db := openEchoDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		input any
+		want  Float128
+	}{
+		{"0.1", Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}},
+		{"0x1.8p+1", Float128{0x4000_8000_0000_0000, 0}},
+		{[]byte("-0.5"), Float128{0xbffe_0000_0000_0000, 0}},
+		{[]byte("1234567890123456"), Float128{0x4031_18b5_4f22_aeb0, 0}},
+		{0.1, Float128{0x3ffb_9999_9999_9999, 0xa000_0000_0000_0000}},
+		{int64(-3), Float128{0xc000_8000_0000_0000, 0}},
+		{Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}, Float128{0x3ffb_9999_9999_9999, 0x9999_9999_9999_999a}},
+	}
+	for _, tt := range tests {
+		var got Float128
+		if err := db.QueryRowContext(ctx, "SELECT ?", tt.input).Scan(SQLScanner(&got)); err != nil {
+			t.Errorf("%v: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%v: got %s, want %s", tt.input, dump(got), dump(tt.want))
+		}
+	}
+
+	// NULL, invalid values, and binary are errors.
+	for _, input := range []any{nil, "1.2.3", true, []byte("1234567890abcdef")} {
+		var got Float128
+		if err := db.QueryRowContext(ctx, "SELECT ?", input).Scan(SQLScanner(&got)); err == nil {
+			t.Errorf("%v: want error, got nil", input)
+		}
+	}
+}
+
+func TestNullFloat128_RoundTrip(t *testing.T) {
+	db := openEchoDB(t)
+	ctx := context.Background()
+
+	r := newXoshiro256pp()
+	n := 1000
+	if testing.Short() {
+		n = 100
+	}
+	for i := 0; i < n; i++ {
+		a, _ := r.Float128Pair()
+		for _, mode := range []StorageMode{StorageText, StorageBinary} {
+			in := NullFloat128{Float128: a, Valid: true, Mode: mode}
+			got := NullFloat128{Mode: mode}
+			if err := db.QueryRowContext(ctx, "SELECT ?", in).Scan(&got); err != nil {
+				t.Errorf("%s: unexpected error: %v", dump(a), err)
+				continue
+			}
+			if !got.Valid || !equals(got.Float128, a) {
+				t.Errorf("mode %d: got %s, want %s", mode, dump(got.Float128), dump(a))
+			}
+		}
+	}
+}
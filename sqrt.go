@@ -5,12 +5,29 @@ import "github.com/shogo82148/int128"
 func (x Float128) Sqrt() Float128 {
 	// special cases
 	switch {
-	case x.IsNaN() || x.IsInf(1) || x.isZero():
+	case x.IsNaN():
+		return propagateNaN(x, x)
+	case x.IsInf(1) || x.isZero():
 		return x
 	case x.h&signMask128H != 0:
 		return NaN()
 	}
 
+	exp, q, rem := x.sqrt()
+
+	// final rounding
+	if rem.H != 0 || rem.L != 0 {
+		q = q.Add(int128.Uint128{H: 0, L: 1})
+	}
+	q = q.Rsh(1)
+	q = q.Add(int128.Uint128{H: uint64(exp-1+bias128) << (shift128 - 64)})
+	return Float128{q.H, q.L}
+}
+
+// sqrt returns the square root of positive finite x,
+// as the 114-bit truncated root q and the remainder rem.
+// The root is q × 2^(exp-113), and it is exact if rem is zero.
+func (x Float128) sqrt() (exp int32, q, rem int128.Uint128) {
 	// normalize x
 	_, exp, frac := x.split()
 
@@ -22,7 +39,7 @@ func (x Float128) Sqrt() Float128 {
 
 	// generate sqrt(frac) bit by bit
 	frac = frac.Lsh(1)
-	var q, s int128.Uint128
+	var s int128.Uint128
 	r := int128.Uint128{H: 1 << (shift128 - 64 + 1)}
 	for r.H != 0 || r.L != 0 {
 		t := s.Add(r)
@@ -34,12 +51,5 @@ func (x Float128) Sqrt() Float128 {
 		frac = frac.Lsh(1)
 		r = r.Rsh(1)
 	}
-
-	// final rounding
-	if frac.H != 0 || frac.L != 0 {
-		q = q.Add(int128.Uint128{H: 0, L: 1})
-	}
-	q = q.Rsh(1)
-	q = q.Add(int128.Uint128{H: uint64(exp-1+bias128) << (shift128 - 64)})
-	return Float128{q.H, q.L}
+	return exp, q, frac
 }
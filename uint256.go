@@ -84,6 +84,17 @@ func (x uint256) rsh(n uint) uint256 {
 	}
 }
 
+// rshSticky returns x >> n, with the least significant bit set if any non-zero bits are shifted out.
+func (x uint256) rshSticky(n uint) uint256 {
+	if n >= 256 {
+		return uint256{d: squash64(x.a | x.b | x.c | x.d)}
+	}
+	lost := x.and(uint256{d: 1}.lsh(n).sub(uint256{d: 1}))
+	x = x.rsh(n)
+	x.d |= squash64(lost.a | lost.b | lost.c | lost.d)
+	return x
+}
+
 func (x uint256) lsh(n uint) uint256 {
 	a, b, c, d := x.a, x.b, x.c, x.d
 	switch {
@@ -125,6 +136,29 @@ func (x uint256) leadingZeros() int {
 	return n
 }
 
+// cmp compares x and y and returns -1, 0, or +1.
+func (x uint256) cmp(y uint256) int {
+	switch {
+	case x == y:
+		return 0
+	case x.a != y.a:
+		return cmpUint64(x.a, y.a)
+	case x.b != y.b:
+		return cmpUint64(x.b, y.b)
+	case x.c != y.c:
+		return cmpUint64(x.c, y.c)
+	}
+	return cmpUint64(x.d, y.d)
+}
+
+// cmpUint64 compares different x and y, and returns -1 or +1.
+func cmpUint64(x, y uint64) int {
+	if x < y {
+		return -1
+	}
+	return 1
+}
+
 func (x uint256) isZero() bool {
 	return (x.a | x.b | x.c | x.d) == 0
 }